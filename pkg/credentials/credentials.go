@@ -0,0 +1,196 @@
+// Package credentials loads the secrets julius uses for authenticated model
+// enumeration and for resolving $API_KEY in generator configs.
+package credentials
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// APIKeyVar is the placeholder replaced by the matched credential's key in
+// credential headers and generator config templates.
+const APIKeyVar = "$API_KEY"
+
+const redacted = "[REDACTED]"
+
+// Credential is one entry of the credentials file. At least one of Service and
+// Host must be set; an entry with both only applies to that service on that
+// host. Host is matched against the target's host:port and hostname and may
+// be a glob (e.g. "*.openai.azure.com").
+type Credential struct {
+	Service   string            `yaml:"service,omitempty"`
+	Host      string            `yaml:"host,omitempty"`
+	APIKey    string            `yaml:"api_key,omitempty"`
+	APIKeyEnv string            `yaml:"api_key_env,omitempty"` // read the key from this environment variable
+	Headers   map[string]string `yaml:"headers,omitempty"`     // defaults to Authorization: Bearer $API_KEY
+}
+
+type file struct {
+	Credentials []Credential `yaml:"credentials"`
+}
+
+type Store struct {
+	entries []Credential
+}
+
+// Load reads a credentials file, resolving api_key_env entries from the
+// environment.
+func Load(filename string) (*Store, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading credentials file: %w", err)
+	}
+	return Parse(data)
+}
+
+func Parse(data []byte) (*Store, error) {
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing credentials YAML: %w", err)
+	}
+
+	for i, c := range f.Credentials {
+		if c.Service == "" && c.Host == "" {
+			return nil, fmt.Errorf("credential %d: service or host is required", i)
+		}
+		if c.APIKeyEnv != "" {
+			key, ok := os.LookupEnv(c.APIKeyEnv)
+			if !ok {
+				return nil, fmt.Errorf("credential %d: environment variable %s is not set", i, c.APIKeyEnv)
+			}
+			f.Credentials[i].APIKey = key
+		}
+		if f.Credentials[i].APIKey == "" && len(c.Headers) == 0 {
+			return nil, fmt.Errorf("credential %d: api_key, api_key_env or headers is required", i)
+		}
+	}
+
+	return &Store{entries: f.Credentials}, nil
+}
+
+// Lookup returns the most specific credential for the service on the target:
+// service and host, then host alone, then service alone. A nil store or no
+// match returns nil.
+func (s *Store) Lookup(target, service string) *Credential {
+	if s == nil {
+		return nil
+	}
+
+	hostPort, hostname := targetHost(target)
+	var byHost, byService *Credential
+	for i := range s.entries {
+		c := &s.entries[i]
+		hostMatch := c.Host != "" && (matchHost(c.Host, hostPort) || matchHost(c.Host, hostname))
+		serviceMatch := c.Service != "" && c.Service == service
+
+		switch {
+		case hostMatch && serviceMatch:
+			return c
+		case hostMatch && c.Service == "" && byHost == nil:
+			byHost = c
+		case serviceMatch && c.Host == "" && byService == nil:
+			byService = c
+		}
+	}
+
+	if byHost != nil {
+		return byHost
+	}
+	return byService
+}
+
+// AuthHeaders returns the headers that authenticate a request with this
+// credential, with $API_KEY resolved.
+func (c *Credential) AuthHeaders() map[string]string {
+	if len(c.Headers) == 0 {
+		return map[string]string{"Authorization": "Bearer " + c.APIKey}
+	}
+	headers := make(map[string]string, len(c.Headers))
+	for k, v := range c.Headers {
+		headers[k] = strings.ReplaceAll(v, APIKeyVar, c.APIKey)
+	}
+	return headers
+}
+
+// Redact replaces every secret held by the store that appears in s.
+func (s *Store) Redact(str string) string {
+	if s == nil || str == "" {
+		return str
+	}
+	for _, c := range s.entries {
+		for _, secret := range c.secrets() {
+			str = strings.ReplaceAll(str, secret, redacted)
+		}
+	}
+	return str
+}
+
+// RedactResults replaces the store's secrets in every generator config, the
+// only result field that carries them.
+func (s *Store) RedactResults(results []types.Result) {
+	if s == nil {
+		return
+	}
+	for i := range results {
+		for j, cfg := range results[i].GeneratorConfigs {
+			results[i].GeneratorConfigs[j] = s.redactConfig(cfg)
+		}
+	}
+}
+
+func (s *Store) redactConfig(cfg types.GeneratorConfig) types.GeneratorConfig {
+	cfg.Endpoint = s.Redact(cfg.Endpoint)
+	cfg.APIKey = s.Redact(cfg.APIKey)
+	cfg.Body = s.Redact(cfg.Body)
+	cfg.Proxy = s.Redact(cfg.Proxy)
+	if cfg.Headers != nil {
+		headers := make(map[string]string, len(cfg.Headers))
+		for k, v := range cfg.Headers {
+			headers[k] = s.Redact(v)
+		}
+		cfg.Headers = headers
+	}
+	cfg.Extra = types.MapExtra(cfg.Extra, s.Redact)
+	return cfg
+}
+
+// secrets lists the literal values that must not appear in output: the key
+// and every resolved header value.
+func (c *Credential) secrets() []string {
+	var secrets []string
+	if c.APIKey != "" {
+		secrets = append(secrets, c.APIKey)
+	}
+	for _, v := range c.AuthHeaders() {
+		if v != "" && v != c.APIKey {
+			secrets = append(secrets, v)
+		}
+	}
+	return secrets
+}
+
+func targetHost(target string) (hostPort, hostname string) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", ""
+	}
+	return u.Host, u.Hostname()
+}
+
+func matchHost(pattern, host string) bool {
+	if host == "" {
+		return false
+	}
+	if strings.EqualFold(pattern, host) {
+		return true
+	}
+	ok, err := path.Match(strings.ToLower(pattern), strings.ToLower(host))
+	return err == nil && ok
+}
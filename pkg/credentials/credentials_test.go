@@ -0,0 +1,111 @@
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+const testCredentials = `
+credentials:
+  - service: azure-openai
+    api_key: azure-service-key
+    headers:
+      api-key: $API_KEY
+  - host: "*.openai.azure.com"
+    service: azure-openai
+    api_key: azure-tenant-key
+    headers:
+      api-key: $API_KEY
+  - host: 10.0.0.5:8000
+    api_key: host-key
+  - service: openai-compatible
+    api_key: generic-key
+`
+
+func TestLookup(t *testing.T) {
+	store, err := Parse([]byte(testCredentials))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		target  string
+		service string
+		wantKey string
+	}{
+		{"service and host glob beats service", "https://acme.openai.azure.com", "azure-openai", "azure-tenant-key"},
+		{"service alone", "https://proxy.example.com", "azure-openai", "azure-service-key"},
+		{"host beats service", "http://10.0.0.5:8000", "openai-compatible", "host-key"},
+		{"host applies to any service", "http://10.0.0.5:8000", "vllm", "host-key"},
+		{"service fallback", "http://10.0.0.6:8000", "openai-compatible", "generic-key"},
+		{"no match", "http://10.0.0.6:8000", "ollama", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cred := store.Lookup(tt.target, tt.service)
+			if tt.wantKey == "" {
+				assert.Nil(t, cred)
+				return
+			}
+			require.NotNil(t, cred)
+			assert.Equal(t, tt.wantKey, cred.APIKey)
+		})
+	}
+
+	var nilStore *Store
+	assert.Nil(t, nilStore.Lookup("https://x", "ollama"), "a nil store matches nothing")
+}
+
+func TestAuthHeaders(t *testing.T) {
+	assert.Equal(t, map[string]string{"Authorization": "Bearer k"}, (&Credential{APIKey: "k"}).AuthHeaders())
+	assert.Equal(t, map[string]string{"api-key": "k"}, (&Credential{APIKey: "k", Headers: map[string]string{"api-key": "$API_KEY"}}).AuthHeaders())
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse([]byte("credentials:\n  - api_key: k\n"))
+	assert.ErrorContains(t, err, "service or host is required")
+
+	_, err = Parse([]byte("credentials:\n  - service: s\n"))
+	assert.ErrorContains(t, err, "api_key")
+
+	_, err = Parse([]byte("credentials:\n  - service: s\n    api_key_env: JULIUS_TEST_UNSET_KEY\n"))
+	assert.ErrorContains(t, err, "JULIUS_TEST_UNSET_KEY")
+}
+
+func TestLoad_APIKeyFromEnv(t *testing.T) {
+	t.Setenv("JULIUS_TEST_KEY", "from-env")
+	path := filepath.Join(t.TempDir(), "creds.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("credentials:\n  - service: groq\n    api_key_env: JULIUS_TEST_KEY\n"), 0600))
+
+	store, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", store.Lookup("https://api.groq.com", "groq").APIKey)
+}
+
+func TestRedactResults(t *testing.T) {
+	store, err := Parse([]byte(testCredentials))
+	require.NoError(t, err)
+
+	results := []types.Result{{
+		Service: "azure-openai",
+		GeneratorConfigs: []types.GeneratorConfig{{
+			Endpoint: "https://acme.openai.azure.com",
+			APIKey:   "azure-tenant-key",
+			Headers:  map[string]string{"Authorization": "Bearer generic-key"},
+			Extra:    map[string]any{"token": "host-key"},
+		}},
+	}}
+
+	store.RedactResults(results)
+
+	gc := results[0].GeneratorConfigs[0]
+	assert.Equal(t, "https://acme.openai.azure.com", gc.Endpoint)
+	assert.Equal(t, "[REDACTED]", gc.APIKey)
+	assert.Equal(t, "Bearer [REDACTED]", gc.Headers["Authorization"])
+	assert.Equal(t, "[REDACTED]", gc.Extra["token"])
+}
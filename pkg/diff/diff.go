@@ -0,0 +1,229 @@
+// Package diff compares two sets of scan results to answer "what changed on
+// the perimeter since the last scan".
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+const (
+	KindAppeared    = "appeared"
+	KindDisappeared = "disappeared"
+	KindChanged     = "changed"
+)
+
+// Change is a single difference for one service on one target.
+type Change struct {
+	Target        string   `json:"target"`
+	Service       string   `json:"service"`
+	Kind          string   `json:"change"`
+	ModelsAdded   []string `json:"models_added,omitempty"`
+	ModelsRemoved []string `json:"models_removed,omitempty"`
+	OldCategory   string   `json:"old_category,omitempty"`
+	NewCategory   string   `json:"new_category,omitempty"`
+	OldAuth       string   `json:"old_auth,omitempty"`
+	NewAuth       string   `json:"new_auth,omitempty"`
+	OldError      string   `json:"old_error,omitempty"`
+	NewError      string   `json:"new_error,omitempty"`
+}
+
+// key identifies a service on a target. Result.Target carries the matched
+// request path, which can legitimately differ between scans of the same
+// service, so it is stripped back to the scanned target.
+type key struct {
+	target  string
+	service string
+}
+
+type entry struct {
+	category string
+	auth     string
+	err      string
+	models   []string
+}
+
+// Compare returns every change between the old and new results, sorted by
+// target and service. Services present in both scans with identical models,
+// category, auth posture and error produce no change.
+func Compare(oldResults, newResults []types.Result) []Change {
+	oldSet := index(oldResults)
+	newSet := index(newResults)
+
+	var changes []Change
+	for k, n := range newSet {
+		o, ok := oldSet[k]
+		if !ok {
+			changes = append(changes, Change{
+				Target:      k.target,
+				Service:     k.service,
+				Kind:        KindAppeared,
+				ModelsAdded: n.models,
+				NewCategory: n.category,
+				NewAuth:     n.auth,
+				NewError:    n.err,
+			})
+			continue
+		}
+		if c, changed := compareEntry(k, o, n); changed {
+			changes = append(changes, c)
+		}
+	}
+	for k, o := range oldSet {
+		if _, ok := newSet[k]; ok {
+			continue
+		}
+		changes = append(changes, Change{
+			Target:        k.target,
+			Service:       k.service,
+			Kind:          KindDisappeared,
+			ModelsRemoved: o.models,
+			OldCategory:   o.category,
+			OldAuth:       o.auth,
+			OldError:      o.err,
+		})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Target != changes[j].Target {
+			return changes[i].Target < changes[j].Target
+		}
+		return changes[i].Service < changes[j].Service
+	})
+	return changes
+}
+
+func compareEntry(k key, o, n entry) (Change, bool) {
+	c := Change{
+		Target:        k.target,
+		Service:       k.service,
+		Kind:          KindChanged,
+		ModelsAdded:   subtract(n.models, o.models),
+		ModelsRemoved: subtract(o.models, n.models),
+	}
+	if o.category != n.category {
+		c.OldCategory, c.NewCategory = o.category, n.category
+	}
+	if o.auth != n.auth {
+		c.OldAuth, c.NewAuth = o.auth, n.auth
+	}
+	if o.err != n.err {
+		c.OldError, c.NewError = o.err, n.err
+	}
+
+	changed := len(c.ModelsAdded) > 0 || len(c.ModelsRemoved) > 0 ||
+		o.category != n.category || o.auth != n.auth || o.err != n.err
+	return c, changed
+}
+
+func index(results []types.Result) map[key]entry {
+	m := make(map[key]entry, len(results))
+	for _, r := range results {
+		k := key{target: r.BaseTarget(), service: r.Service}
+		e := m[k]
+		e.category = r.Category
+		if r.Auth != "" {
+			e.auth = r.Auth
+		}
+		if r.Error != "" {
+			e.err = r.Error
+		}
+		for _, model := range r.Models {
+			if !slices.Contains(e.models, model) {
+				e.models = append(e.models, model)
+			}
+		}
+		m[k] = e
+	}
+	for k, e := range m {
+		sort.Strings(e.models)
+		m[k] = e
+	}
+	return m
+}
+
+// subtract returns the elements of a not present in b.
+func subtract(a, b []string) []string {
+	var out []string
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Write renders changes in the given format (table, json or jsonl).
+func Write(w io.Writer, format string, changes []Change) error {
+	switch format {
+	case "table":
+		return writeTable(w, changes)
+	case "json":
+		if changes == nil {
+			changes = []Change{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(changes)
+	case "jsonl":
+		encoder := json.NewEncoder(w)
+		for _, c := range changes {
+			if err := encoder.Encode(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown format: %s", format)
+	}
+}
+
+func writeTable(w io.Writer, changes []Change) error {
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(w, "No changes")
+		return err
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"TARGET", "SERVICE", "CHANGE", "DETAILS"})
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+
+	for _, c := range changes {
+		table.Append([]string{c.Target, c.Service, c.Kind, details(c)})
+	}
+
+	table.Render()
+	return nil
+}
+
+func details(c Change) string {
+	var parts []string
+	if len(c.ModelsAdded) > 0 {
+		parts = append(parts, "+models: "+strings.Join(c.ModelsAdded, ", "))
+	}
+	if len(c.ModelsRemoved) > 0 {
+		parts = append(parts, "-models: "+strings.Join(c.ModelsRemoved, ", "))
+	}
+	if c.Kind == KindChanged && (c.OldCategory != "" || c.NewCategory != "") {
+		parts = append(parts, fmt.Sprintf("category: %s -> %s", c.OldCategory, c.NewCategory))
+	}
+	if c.Kind == KindChanged && (c.OldAuth != "" || c.NewAuth != "") {
+		parts = append(parts, fmt.Sprintf("auth: %s -> %s", c.OldAuth, c.NewAuth))
+	}
+	if c.Kind == KindChanged && (c.OldError != "" || c.NewError != "") {
+		parts = append(parts, fmt.Sprintf("error: %q -> %q", c.OldError, c.NewError))
+	}
+	if c.Kind == KindAppeared && c.NewError != "" {
+		parts = append(parts, "error: "+c.NewError)
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,107 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+func TestCompare(t *testing.T) {
+	oldResults := []types.Result{
+		{Target: "https://a.example.com/api/tags", MatchedRequest: "/api/tags", Service: "ollama", Category: "self-hosted", Models: []string{"llama3", "mistral"}},
+		{Target: "https://b.example.com/v1/models", MatchedRequest: "/v1/models", Service: "vllm", Category: "self-hosted"},
+		{Target: "https://c.example.com/health", MatchedRequest: "/health", Service: "litellm", Category: "gateway", Error: "models request returned 401"},
+		{Target: "https://d.example.com/", MatchedRequest: "/", Service: "jan", Category: "self-hosted", Models: []string{"a"}},
+	}
+	newResults := []types.Result{
+		// Same service matched on a different request path: still the same target.
+		{Target: "https://a.example.com/", MatchedRequest: "/", Service: "ollama", Category: "self-hosted", Models: []string{"mistral", "qwen2.5"}},
+		{Target: "https://c.example.com/health", MatchedRequest: "/health", Service: "litellm", Category: "gateway", Models: []string{"gpt-4o"}},
+		{Target: "https://d.example.com/", MatchedRequest: "/", Service: "jan", Category: "self-hosted", Models: []string{"a"}},
+		{Target: "https://e.example.com", Service: "mcp-server", Category: "mcp"},
+	}
+
+	changes := Compare(oldResults, newResults)
+
+	assert.Equal(t, []Change{
+		{Target: "https://a.example.com", Service: "ollama", Kind: KindChanged, ModelsAdded: []string{"qwen2.5"}, ModelsRemoved: []string{"llama3"}},
+		{Target: "https://b.example.com", Service: "vllm", Kind: KindDisappeared, OldCategory: "self-hosted"},
+		{Target: "https://c.example.com", Service: "litellm", Kind: KindChanged, ModelsAdded: []string{"gpt-4o"}, OldError: "models request returned 401"},
+		{Target: "https://e.example.com", Service: "mcp-server", Kind: KindAppeared, NewCategory: "mcp"},
+	}, changes)
+}
+
+func TestCompare_CategoryChange(t *testing.T) {
+	changes := Compare(
+		[]types.Result{{Target: "https://x", Service: "s", Category: "generic"}},
+		[]types.Result{{Target: "https://x", Service: "s", Category: "gateway"}},
+	)
+	require.Len(t, changes, 1)
+	assert.Equal(t, KindChanged, changes[0].Kind)
+	assert.Equal(t, "generic", changes[0].OldCategory)
+	assert.Equal(t, "gateway", changes[0].NewCategory)
+}
+
+func TestCompare_AuthChange(t *testing.T) {
+	changes := Compare(
+		[]types.Result{{Target: "https://x", Service: "s", Auth: types.AuthRequired}},
+		[]types.Result{{Target: "https://x", Service: "s", Auth: types.AuthOpen}},
+	)
+	require.Len(t, changes, 1)
+	assert.Equal(t, KindChanged, changes[0].Kind)
+	assert.Equal(t, types.AuthRequired, changes[0].OldAuth)
+	assert.Equal(t, types.AuthOpen, changes[0].NewAuth)
+	assert.Equal(t, "auth: auth-required -> open", details(changes[0]))
+}
+
+func TestCompare_NoChanges(t *testing.T) {
+	results := []types.Result{{Target: "https://x/v1/models", MatchedRequest: "/v1/models", Service: "s", Models: []string{"b", "a"}}}
+	reordered := []types.Result{{Target: "https://x/v1/models", MatchedRequest: "/v1/models", Service: "s", Models: []string{"a", "b"}}}
+	assert.Empty(t, Compare(results, reordered))
+	assert.Empty(t, Compare(nil, nil))
+}
+
+func TestWrite(t *testing.T) {
+	changes := []Change{
+		{Target: "https://a", Service: "ollama", Kind: KindAppeared, ModelsAdded: []string{"llama3"}},
+		{Target: "https://b", Service: "vllm", Kind: KindChanged, OldError: "timeout"},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, Write(buf, "table", changes))
+		out := buf.String()
+		assert.Contains(t, out, "CHANGE")
+		assert.Contains(t, out, "+models: llama3")
+		assert.Contains(t, out, `error: "timeout" -> ""`)
+	})
+
+	t.Run("table empty", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, Write(buf, "table", nil))
+		assert.Contains(t, buf.String(), "No changes")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, Write(buf, "json", changes))
+		var decoded []Change
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, changes, decoded)
+	})
+
+	t.Run("json empty is an array", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		require.NoError(t, Write(buf, "json", nil))
+		assert.JSONEq(t, "[]", buf.String())
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		assert.Error(t, Write(&bytes.Buffer{}, "html", changes))
+	})
+}
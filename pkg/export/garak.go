@@ -0,0 +1,93 @@
+package export
+
+import (
+	"strings"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// GarakConfig is everything needed for one garak run against a discovered
+// model: `garak --model_type <ModelType> --model_name <ModelName>
+// --generator_option_file <Options as JSON>`.
+type GarakConfig struct {
+	ModelType string
+	ModelName string
+	Options   map[string]any
+}
+
+// ToGarakConfig maps a resolved Augustus generator config onto the equivalent
+// garak generator. openai becomes openai.OpenAICompatible, ollama becomes
+// ollama.OllamaGeneratorChat and rest becomes rest.RestGenerator with the
+// body's $PROMPT rewritten to garak's $INPUT. Other generator types return
+// ErrUnsupportedGenerator.
+func ToGarakConfig(cfg types.GeneratorConfig) (*GarakConfig, error) {
+	switch cfg.Type {
+	case "openai":
+		opts := map[string]any{
+			"uri":   types.OpenAIBaseURL(cfg.Endpoint),
+			"model": cfg.Model,
+		}
+		if cfg.APIKey != "" {
+			opts["api_key"] = cfg.APIKey
+		}
+		return &GarakConfig{
+			ModelType: "openai.OpenAICompatible",
+			ModelName: cfg.Model,
+			Options:   garakOptions("openai", "OpenAICompatible", opts),
+		}, nil
+
+	case "ollama":
+		return &GarakConfig{
+			ModelType: "ollama.OllamaGeneratorChat",
+			ModelName: cfg.Model,
+			Options:   garakOptions("ollama", "OllamaGeneratorChat", map[string]any{"host": cfg.Endpoint}),
+		}, nil
+
+	case "rest":
+		opts := map[string]any{
+			"uri":          cfg.Endpoint,
+			"method":       strings.ToLower(defaultString(cfg.Method, "POST")),
+			"req_template": strings.ReplaceAll(cfg.Body, types.PromptVar, "$INPUT"),
+		}
+		headers := make(map[string]string, len(cfg.Headers)+1)
+		for k, v := range cfg.Headers {
+			headers[k] = v
+		}
+		if cfg.APIKey != "" {
+			// RestGenerator substitutes $KEY in headers with api_key.
+			opts["api_key"] = cfg.APIKey
+			if _, ok := headers["Authorization"]; !ok {
+				headers["Authorization"] = "Bearer $KEY"
+			}
+		}
+		if len(headers) > 0 {
+			opts["headers"] = headers
+		}
+		if cfg.ResponsePath != "" {
+			opts["response_json"] = true
+			opts["response_json_field"] = cfg.ResponsePath
+		}
+		if cfg.Timeout > 0 {
+			opts["request_timeout"] = cfg.Timeout
+		}
+		return &GarakConfig{
+			ModelType: "rest.RestGenerator",
+			ModelName: cfg.Model,
+			Options:   garakOptions("rest", "RestGenerator", opts),
+		}, nil
+	}
+	return nil, unsupported("garak", cfg.Type)
+}
+
+// garakOptions nests generator options the way garak's option files expect:
+// {module: {Class: {...}}}.
+func garakOptions(module, class string, opts map[string]any) map[string]any {
+	return map[string]any{module: map[string]any{class: opts}}
+}
+
+func defaultString(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
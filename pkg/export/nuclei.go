@@ -0,0 +1,243 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/praetorian-inc/julius/pkg/rules"
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// NucleiTemplate is the subset of the Nuclei HTTP template schema that a julius
+// probe maps onto. Field order matches the order Nuclei's own templates use.
+type NucleiTemplate struct {
+	ID   string          `yaml:"id"`
+	Info NucleiInfo      `yaml:"info"`
+	Flow string          `yaml:"flow,omitempty"`
+	HTTP []NucleiRequest `yaml:"http"`
+}
+
+type NucleiInfo struct {
+	Name      string         `yaml:"name"`
+	Author    string         `yaml:"author"`
+	Severity  string         `yaml:"severity"`
+	Reference []string       `yaml:"reference,omitempty"`
+	Metadata  map[string]any `yaml:"metadata,omitempty"`
+	Tags      string         `yaml:"tags"`
+}
+
+type NucleiRequest struct {
+	Method            string            `yaml:"method"`
+	Path              []string          `yaml:"path"`
+	Headers           map[string]string `yaml:"headers,omitempty"`
+	Body              string            `yaml:"body,omitempty"`
+	MatchersCondition string            `yaml:"matchers-condition,omitempty"`
+	Matchers          []NucleiMatcher   `yaml:"matchers,omitempty"`
+	Extractors        []NucleiExtractor `yaml:"extractors,omitempty"`
+}
+
+type NucleiMatcher struct {
+	Type            string   `yaml:"type"`
+	Part            string   `yaml:"part,omitempty"`
+	Status          []int    `yaml:"status,omitempty"`
+	Words           []string `yaml:"words,omitempty"`
+	Regex           []string `yaml:"regex,omitempty"`
+	Negative        bool     `yaml:"negative,omitempty"`
+	CaseInsensitive bool     `yaml:"case-insensitive,omitempty"`
+}
+
+type NucleiExtractor struct {
+	Type string   `yaml:"type"`
+	Name string   `yaml:"name,omitempty"`
+	Part string   `yaml:"part,omitempty"`
+	JSON []string `yaml:"json,omitempty"`
+}
+
+// ToNucleiTemplate converts a probe into an equivalent Nuclei HTTP template.
+//
+// Each probe request becomes one http block whose match rules are ANDed
+// (matchers-condition: and), mirroring julius where every rule in a request
+// must match. Across requests, require:any and require:all are expressed with a
+// Nuclei v3 flow: `http(1) || http(2)` short-circuits on the first match the
+// same way matchProbeAny does, and `http(1) && http(2)` requires every request.
+// When the probe declares `models:`, the models request is appended as a final
+// block carrying a json extractor and the flow only reaches it after detection
+// succeeds, so a generic extract expression never reports on its own.
+func ToNucleiTemplate(p *types.Probe) (*NucleiTemplate, error) {
+	if len(p.Requests) == 0 {
+		return nil, fmt.Errorf("probe %s has no requests", p.Name)
+	}
+
+	tmpl := &NucleiTemplate{
+		ID: "julius-" + p.Name,
+		Info: NucleiInfo{
+			Name:     p.Description,
+			Author:   "julius",
+			Severity: "info",
+			Metadata: map[string]any{
+				"max-request": len(p.Requests),
+				"specificity": p.GetSpecificity(),
+			},
+			Tags: nucleiTags(p),
+		},
+	}
+	if tmpl.Info.Name == "" {
+		tmpl.Info.Name = p.Name
+	}
+	if p.APIDocs != "" {
+		tmpl.Info.Reference = []string{p.APIDocs}
+	}
+	if p.PortHint != 0 {
+		tmpl.Info.Metadata["port-hint"] = p.PortHint
+	}
+
+	for i, req := range p.Requests {
+		req.ApplyDefaults()
+		if req.Type != types.RequestTypeHTTP {
+			return nil, fmt.Errorf("%w: probe %s request %d has type %s, which nuclei cannot read", ErrUnsupportedProbe, p.Name, i, req.Type)
+		}
+
+		matchers, err := nucleiMatchers(req)
+		if err != nil {
+			return nil, fmt.Errorf("probe %s request %d: %w", p.Name, i, err)
+		}
+
+		tmpl.HTTP = append(tmpl.HTTP, NucleiRequest{
+			Method:            req.Method,
+			Path:              []string{"{{BaseURL}}" + req.Path},
+			Headers:           req.Headers,
+			Body:              req.Body,
+			MatchersCondition: "and",
+			Matchers:          matchers,
+		})
+	}
+
+	if p.Models != nil {
+		method := p.Models.Method
+		if method == "" {
+			method = "GET"
+		}
+		tmpl.HTTP = append(tmpl.HTTP, NucleiRequest{
+			Method:  method,
+			Path:    []string{"{{BaseURL}}" + p.Models.Path},
+			Headers: p.Models.Headers,
+			Body:    p.Models.Body,
+			Extractors: []NucleiExtractor{{
+				Type: "json",
+				Name: "models",
+				Part: "body",
+				JSON: []string{p.Models.Extract},
+			}},
+		})
+		tmpl.Info.Metadata["max-request"] = len(tmpl.HTTP)
+	}
+
+	tmpl.Flow = nucleiFlow(p)
+	return tmpl, nil
+}
+
+// MarshalNuclei renders a template as YAML ready to be dropped into a Nuclei
+// templates directory.
+func MarshalNuclei(tmpl *NucleiTemplate) ([]byte, error) {
+	data, err := yaml.Marshal(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling nuclei template %s: %w", tmpl.ID, err)
+	}
+	return data, nil
+}
+
+// nucleiFlow returns the flow expression for the probe, or "" when Nuclei's
+// default behaviour (every block evaluated independently, any match reports)
+// already equals require:any.
+func nucleiFlow(p *types.Probe) string {
+	if !p.RequiresAll() && p.Models == nil {
+		return ""
+	}
+
+	calls := make([]string, len(p.Requests))
+	for i := range p.Requests {
+		calls[i] = fmt.Sprintf("http(%d)", i+1)
+	}
+
+	var flow string
+	if p.RequiresAll() {
+		flow = strings.Join(calls, " && ")
+	} else {
+		flow = strings.Join(calls, " || ")
+		if len(calls) > 1 {
+			flow = "(" + flow + ")"
+		}
+	}
+
+	if p.Models != nil {
+		flow += fmt.Sprintf(" && http(%d)", len(p.Requests)+1)
+	}
+	return flow
+}
+
+func nucleiTags(p *types.Probe) string {
+	tags := []string{"julius", "llm", "tech"}
+	if p.Category != "" {
+		tags = append(tags, p.Category)
+	}
+	tags = append(tags, p.Tags...)
+	return strings.Join(tags, ",")
+}
+
+// nucleiMatchers translates the request's match rules one-to-one. Rules are
+// compiled first so values are type-checked exactly as the scanner checks them.
+// Header rules address the header directly through Nuclei's per-header part
+// names (lower-cased, dashes as underscores).
+func nucleiMatchers(req types.Request) ([]NucleiMatcher, error) {
+	ruleList, err := req.GetRules()
+	if err != nil {
+		return nil, err
+	}
+
+	matchers := make([]NucleiMatcher, 0, len(ruleList))
+	for i, rule := range ruleList {
+		m := NucleiMatcher{Negative: rule.IsNegated()}
+		switch r := rule.(type) {
+		case *rules.StatusRule:
+			m.Type = "status"
+			m.Status = []int{r.Status}
+		case *rules.BodyContainsRule:
+			m.Type = "word"
+			m.Part = "body"
+			m.Words = []string{r.Value}
+		case *rules.BodyPrefixRule:
+			m.Type = "regex"
+			m.Part = "body"
+			m.Regex = []string{"^" + regexp.QuoteMeta(r.Value)}
+		case *rules.ContentTypeRule:
+			m.Type = "word"
+			m.Part = "content_type"
+			m.Words = []string{r.Value}
+			m.CaseInsensitive = true
+		case *rules.HeaderContainsRule:
+			m.Part = nucleiHeaderPart(r.Header)
+			if r.Value != "" {
+				m.Type = "word"
+				m.Words = []string{r.Value}
+			} else {
+				// An empty value matches any non-empty header, i.e. presence.
+				m.Type = "regex"
+				m.Regex = []string{"."}
+			}
+		case *rules.HeaderPrefixRule:
+			m.Type = "regex"
+			m.Part = nucleiHeaderPart(r.Header)
+			m.Regex = []string{"^" + regexp.QuoteMeta(r.Value)}
+		default:
+			return nil, fmt.Errorf("rule %d: no nuclei equivalent for rule type %s", i, rule.GetType())
+		}
+		matchers = append(matchers, m)
+	}
+	return matchers, nil
+}
+
+func nucleiHeaderPart(header string) string {
+	return strings.ReplaceAll(strings.ToLower(header), "-", "_")
+}
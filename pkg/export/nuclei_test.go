@@ -0,0 +1,188 @@
+package export
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/probe"
+	"github.com/praetorian-inc/julius/pkg/rules"
+	"github.com/praetorian-inc/julius/pkg/types"
+	"github.com/praetorian-inc/julius/probes"
+)
+
+func TestToNucleiTemplate_MatchersAndExtractor(t *testing.T) {
+	p, err := probe.ParseProbe([]byte(`
+name: test-llm
+description: Test LLM server
+category: self-hosted
+port_hint: 8080
+specificity: 80
+require: all
+api_docs: https://example.com/docs
+requests:
+  - path: /
+    match:
+      - type: status
+        value: 200
+      - type: body.prefix
+        value: '{"object"'
+  - path: /api/chat
+    method: POST
+    headers:
+      Content-Type: application/json
+    body: '{"model":"x"}'
+    match:
+      - type: content-type
+        value: application/json
+      - type: header.contains
+        header: X-Served-By
+        value: test-llm
+      - type: header.prefix
+        header: Server
+        value: uvicorn
+        not: true
+models:
+  path: /api/models
+  extract: ".models[].name"
+`))
+	require.NoError(t, err)
+
+	tmpl, err := ToNucleiTemplate(p)
+	require.NoError(t, err)
+
+	assert.Equal(t, "julius-test-llm", tmpl.ID)
+	assert.Equal(t, "Test LLM server", tmpl.Info.Name)
+	assert.Equal(t, "info", tmpl.Info.Severity)
+	assert.Equal(t, []string{"https://example.com/docs"}, tmpl.Info.Reference)
+	assert.Equal(t, "julius,llm,tech,self-hosted", tmpl.Info.Tags)
+	assert.Equal(t, 8080, tmpl.Info.Metadata["port-hint"])
+	assert.Equal(t, "http(1) && http(2) && http(3)", tmpl.Flow)
+
+	require.Len(t, tmpl.HTTP, 3)
+
+	first := tmpl.HTTP[0]
+	assert.Equal(t, "GET", first.Method)
+	assert.Equal(t, []string{"{{BaseURL}}/"}, first.Path)
+	assert.Equal(t, "and", first.MatchersCondition)
+	assert.Equal(t, []NucleiMatcher{
+		{Type: "status", Status: []int{200}},
+		{Type: "regex", Part: "body", Regex: []string{`^\{"object"`}},
+	}, first.Matchers)
+
+	second := tmpl.HTTP[1]
+	assert.Equal(t, "POST", second.Method)
+	assert.Equal(t, `{"model":"x"}`, second.Body)
+	assert.Equal(t, "application/json", second.Headers["Content-Type"])
+	assert.Equal(t, []NucleiMatcher{
+		{Type: "word", Part: "content_type", Words: []string{"application/json"}, CaseInsensitive: true},
+		{Type: "word", Part: "x_served_by", Words: []string{"test-llm"}},
+		{Type: "regex", Part: "server", Regex: []string{"^uvicorn"}, Negative: true},
+	}, second.Matchers)
+
+	models := tmpl.HTTP[2]
+	assert.Equal(t, "GET", models.Method)
+	assert.Equal(t, []string{"{{BaseURL}}/api/models"}, models.Path)
+	assert.Empty(t, models.Matchers)
+	assert.Equal(t, []NucleiExtractor{{Type: "json", Name: "models", Part: "body", JSON: []string{".models[].name"}}}, models.Extractors)
+}
+
+func TestToNucleiTemplate_Flow(t *testing.T) {
+	req := types.Request{Path: "/", RawMatch: []rules.RawRule{{Type: "status", Value: 200}}}
+
+	tests := []struct {
+		name    string
+		require string
+		count   int
+		models  bool
+		want    string
+	}{
+		{"any without models needs no flow", "any", 2, false, ""},
+		{"any with models gates extraction", "any", 2, true, "(http(1) || http(2)) && http(3)"},
+		{"single any with models", "", 1, true, "http(1) && http(2)"},
+		{"all without models", "all", 2, false, "http(1) && http(2)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &types.Probe{Name: "flow", Require: tt.require}
+			for i := 0; i < tt.count; i++ {
+				p.Requests = append(p.Requests, req)
+			}
+			if tt.models {
+				p.Models = &types.ModelsConfig{Path: "/v1/models", Extract: ".data[].id"}
+			}
+
+			tmpl, err := ToNucleiTemplate(p)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tmpl.Flow)
+		})
+	}
+}
+
+func TestToNucleiTemplate_EmptyHeaderValueMatchesPresence(t *testing.T) {
+	p := &types.Probe{
+		Name: "mcp",
+		Requests: []types.Request{{
+			Path:     "",
+			RawMatch: []rules.RawRule{{Type: "header.contains", Header: "MCP-Protocol-Version", Value: ""}},
+		}},
+	}
+
+	tmpl, err := ToNucleiTemplate(p)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"{{BaseURL}}"}, tmpl.HTTP[0].Path, "empty path targets the supplied URL as-is")
+	assert.Equal(t, []NucleiMatcher{{Type: "regex", Part: "mcp_protocol_version", Regex: []string{"."}}}, tmpl.HTTP[0].Matchers)
+}
+
+func TestToNucleiTemplate_Errors(t *testing.T) {
+	t.Run("no requests", func(t *testing.T) {
+		_, err := ToNucleiTemplate(&types.Probe{Name: "empty"})
+		assert.Error(t, err)
+	})
+
+	t.Run("sse request", func(t *testing.T) {
+		p := &types.Probe{
+			Name:     "stream",
+			Requests: []types.Request{{Type: types.RequestTypeSSE, RawMatch: []rules.RawRule{{Type: "status", Value: 200}}}},
+		}
+		_, err := ToNucleiTemplate(p)
+		assert.ErrorIs(t, err, ErrUnsupportedProbe)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		p := &types.Probe{
+			Name:     "bad",
+			Requests: []types.Request{{Path: "/", RawMatch: []rules.RawRule{{Type: "status", Value: "200"}}}},
+		}
+		_, err := ToNucleiTemplate(p)
+		assert.Error(t, err)
+	})
+}
+
+// TestToNucleiTemplate_EmbeddedProbes converts every shipped probe and checks
+// the rendered YAML round-trips, so a new rule type or probe shape that has no
+// Nuclei mapping fails here rather than at export time.
+func TestToNucleiTemplate_EmbeddedProbes(t *testing.T) {
+	loaded, err := probe.LoadProbesFromFS(probes.EmbeddedProbes, ".")
+	require.NoError(t, err)
+
+	for _, p := range loaded {
+		tmpl, err := ToNucleiTemplate(p)
+		if errors.Is(err, ErrUnsupportedProbe) {
+			continue
+		}
+		require.NoError(t, err, "probe %s", p.Name)
+
+		data, err := MarshalNuclei(tmpl)
+		require.NoError(t, err)
+
+		var decoded NucleiTemplate
+		require.NoError(t, yaml.Unmarshal(data, &decoded), "probe %s", p.Name)
+		assert.Equal(t, tmpl.ID, decoded.ID)
+		assert.Len(t, decoded.HTTP, len(tmpl.HTTP))
+	}
+}
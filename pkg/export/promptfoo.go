@@ -0,0 +1,77 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// PromptfooProvider is one entry of a promptfoo `providers:` list.
+type PromptfooProvider struct {
+	ID     string            `yaml:"id" json:"id"`
+	Label  string            `yaml:"label,omitempty" json:"label,omitempty"`
+	Config map[string]any    `yaml:"config,omitempty" json:"config,omitempty"`
+	Env    map[string]string `yaml:"env,omitempty" json:"env,omitempty"`
+}
+
+// ToPromptfooProvider maps a resolved Augustus generator config onto a
+// promptfoo provider. openai becomes openai:chat:<model> pointed at the
+// endpoint, ollama becomes ollama:chat:<model> with OLLAMA_BASE_URL set, and
+// rest becomes the generic http provider with $PROMPT rewritten to
+// {{prompt}}. Other generator types return ErrUnsupportedGenerator.
+func ToPromptfooProvider(cfg types.GeneratorConfig, label string) (*PromptfooProvider, error) {
+	switch cfg.Type {
+	case "openai":
+		config := map[string]any{"apiBaseUrl": types.OpenAIBaseURL(cfg.Endpoint)}
+		if cfg.APIKey != "" {
+			config["apiKey"] = cfg.APIKey
+		}
+		return &PromptfooProvider{ID: "openai:chat:" + cfg.Model, Label: label, Config: config}, nil
+
+	case "ollama":
+		return &PromptfooProvider{
+			ID:    "ollama:chat:" + cfg.Model,
+			Label: label,
+			Env:   map[string]string{"OLLAMA_BASE_URL": cfg.Endpoint},
+		}, nil
+
+	case "rest":
+		config := map[string]any{
+			"url":    cfg.Endpoint,
+			"method": defaultString(cfg.Method, "POST"),
+		}
+		headers := make(map[string]string, len(cfg.Headers)+1)
+		for k, v := range cfg.Headers {
+			headers[k] = v
+		}
+		if cfg.APIKey != "" {
+			if _, ok := headers["Authorization"]; !ok {
+				headers["Authorization"] = "Bearer " + cfg.APIKey
+			}
+		}
+		if len(headers) > 0 {
+			config["headers"] = headers
+		}
+		if cfg.Body != "" {
+			config["body"] = promptfooBody(cfg.Body)
+		}
+		if cfg.ResponsePath != "" {
+			config["transformResponse"] = "json." + cfg.ResponsePath
+		}
+		return &PromptfooProvider{ID: "http", Label: label, Config: config}, nil
+	}
+	return nil, unsupported("promptfoo", cfg.Type)
+}
+
+// promptfooBody rewrites the prompt placeholder. A JSON body is decoded into
+// an object so promptfoo escapes the prompt when it renders each string;
+// anything else is passed through as a string template.
+func promptfooBody(body string) any {
+	body = strings.ReplaceAll(body, types.PromptVar, "{{prompt}}")
+	var obj map[string]any
+	if err := json.Unmarshal([]byte(body), &obj); err == nil {
+		return obj
+	}
+	return body
+}
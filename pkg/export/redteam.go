@@ -0,0 +1,19 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedGenerator is returned when an Augustus generator type has no
+// equivalent in the target tool (e.g. garak has no MCP generator).
+var ErrUnsupportedGenerator = errors.New("unsupported generator")
+
+// ErrUnsupportedProbe is returned when a probe cannot be expressed in the
+// target format (e.g. an sse request, which Nuclei would wait on until it
+// timed out).
+var ErrUnsupportedProbe = errors.New("unsupported probe")
+
+func unsupported(tool, generator string) error {
+	return fmt.Errorf("%w: %s has no equivalent for %q", ErrUnsupportedGenerator, tool, generator)
+}
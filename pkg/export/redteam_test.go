@@ -0,0 +1,112 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+func TestToGarakConfig(t *testing.T) {
+	t.Run("openai", func(t *testing.T) {
+		got, err := ToGarakConfig(types.GeneratorConfig{Type: "openai", Endpoint: "http://host:8000", Model: "gpt-4o", APIKey: "sk-test"})
+		require.NoError(t, err)
+		assert.Equal(t, "openai.OpenAICompatible", got.ModelType)
+		assert.Equal(t, "gpt-4o", got.ModelName)
+		assert.Equal(t, map[string]any{"openai": map[string]any{"OpenAICompatible": map[string]any{
+			"uri": "http://host:8000/v1", "model": "gpt-4o", "api_key": "sk-test",
+		}}}, got.Options)
+	})
+
+	t.Run("openai endpoint with path is kept", func(t *testing.T) {
+		got, err := ToGarakConfig(types.GeneratorConfig{Type: "openai", Endpoint: "http://host/openai/v1", Model: "m"})
+		require.NoError(t, err)
+		assert.Equal(t, "http://host/openai/v1", got.Options["openai"].(map[string]any)["OpenAICompatible"].(map[string]any)["uri"])
+	})
+
+	t.Run("ollama", func(t *testing.T) {
+		got, err := ToGarakConfig(types.GeneratorConfig{Type: "ollama", Endpoint: "http://host:11434", Model: "llama3"})
+		require.NoError(t, err)
+		assert.Equal(t, "ollama.OllamaGeneratorChat", got.ModelType)
+		assert.Equal(t, map[string]any{"ollama": map[string]any{"OllamaGeneratorChat": map[string]any{"host": "http://host:11434"}}}, got.Options)
+	})
+
+	t.Run("rest", func(t *testing.T) {
+		got, err := ToGarakConfig(types.GeneratorConfig{
+			Type:         "rest",
+			Endpoint:     "http://host/generate",
+			Method:       "POST",
+			APIKey:       "r8-key",
+			Headers:      map[string]string{"Content-Type": "application/json"},
+			Body:         `{"inputs": "$PROMPT"}`,
+			ResponsePath: "generated_text",
+			Timeout:      180,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "rest.RestGenerator", got.ModelType)
+		assert.Equal(t, map[string]any{"rest": map[string]any{"RestGenerator": map[string]any{
+			"uri":                 "http://host/generate",
+			"method":              "post",
+			"req_template":        `{"inputs": "$INPUT"}`,
+			"api_key":             "r8-key",
+			"headers":             map[string]string{"Content-Type": "application/json", "Authorization": "Bearer $KEY"},
+			"response_json":       true,
+			"response_json_field": "generated_text",
+			"request_timeout":     180,
+		}}}, got.Options)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := ToGarakConfig(types.GeneratorConfig{Type: "mcp"})
+		assert.ErrorIs(t, err, ErrUnsupportedGenerator)
+	})
+}
+
+func TestToPromptfooProvider(t *testing.T) {
+	t.Run("openai", func(t *testing.T) {
+		got, err := ToPromptfooProvider(types.GeneratorConfig{Type: "openai", Endpoint: "http://host:8000/", Model: "gpt-4o", APIKey: "sk-test"}, "vllm")
+		require.NoError(t, err)
+		assert.Equal(t, &PromptfooProvider{
+			ID:     "openai:chat:gpt-4o",
+			Label:  "vllm",
+			Config: map[string]any{"apiBaseUrl": "http://host:8000/v1", "apiKey": "sk-test"},
+		}, got)
+	})
+
+	t.Run("ollama", func(t *testing.T) {
+		got, err := ToPromptfooProvider(types.GeneratorConfig{Type: "ollama", Endpoint: "http://host:11434", Model: "llama3"}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "ollama:chat:llama3", got.ID)
+		assert.Equal(t, map[string]string{"OLLAMA_BASE_URL": "http://host:11434"}, got.Env)
+	})
+
+	t.Run("rest", func(t *testing.T) {
+		got, err := ToPromptfooProvider(types.GeneratorConfig{
+			Type:         "rest",
+			Endpoint:     "http://host/generate",
+			Body:         `{"inputs": "$PROMPT", "parameters": {"max_new_tokens": 512}}`,
+			ResponsePath: "generated_text",
+		}, "tgi")
+		require.NoError(t, err)
+		assert.Equal(t, "http", got.ID)
+		assert.Equal(t, map[string]any{
+			"url":               "http://host/generate",
+			"method":            "POST",
+			"body":              map[string]any{"inputs": "{{prompt}}", "parameters": map[string]any{"max_new_tokens": float64(512)}},
+			"transformResponse": "json.generated_text",
+		}, got.Config)
+	})
+
+	t.Run("non-JSON body stays a template", func(t *testing.T) {
+		got, err := ToPromptfooProvider(types.GeneratorConfig{Type: "rest", Endpoint: "http://host", Body: "prompt=$PROMPT"}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "prompt={{prompt}}", got.Config["body"])
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := ToPromptfooProvider(types.GeneratorConfig{Type: "mcp"}, "")
+		assert.ErrorIs(t, err, ErrUnsupportedGenerator)
+	})
+}
@@ -0,0 +1,149 @@
+// Package fixture implements the golden-file format used to test probes
+// against canned HTTP responses.
+//
+// A fixture file names one probe and lists cases. Each case maps request paths
+// to the responses a local server returns for them, and states whether the
+// probe must match. Running a case serves those responses and hands the
+// server's URL to a scan function, so the real probe goes through the real
+// scanner.
+package fixture
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// Fixture is the golden file for a single probe.
+type Fixture struct {
+	Probe string `yaml:"probe"`
+	Cases []Case `yaml:"cases"`
+
+	// Path is the file the fixture was loaded from, for messages.
+	Path string `yaml:"-"`
+}
+
+// Case is one scenario: a set of canned responses and the match result the
+// probe must produce against them. Auth, when set on a positive case, is the
+// authentication posture the result must report.
+type Case struct {
+	Name      string              `yaml:"name"`
+	Match     bool                `yaml:"match"`
+	Auth      string              `yaml:"auth,omitempty"`
+	Responses map[string]Response `yaml:"responses"`
+}
+
+// Response is the canned HTTP response served for a given request path.
+// Method, when set, restricts the response to requests using that HTTP method;
+// a mismatch is served 405 so a probe whose method regresses (e.g. POST->GET)
+// no longer receives the canned body. Empty means any method is accepted.
+type Response struct {
+	Status  int               `yaml:"status"`
+	Method  string            `yaml:"method,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Body    string            `yaml:"body,omitempty"`
+}
+
+// Load reads every .yaml and .yml fixture in dir. Unknown keys are errors, so
+// a misspelled "match:" cannot silently turn a case negative.
+func Load(dir string) ([]Fixture, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture directory: %w", err)
+	}
+
+	var fixtures []Fixture
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		fx, err := ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		fixtures = append(fixtures, fx)
+	}
+	return fixtures, nil
+}
+
+// ReadFile reads the fixture at path.
+func ReadFile(path string) (Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Fixture{}, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	var fx Fixture
+	if err := yaml.UnmarshalWithOptions(data, &fx, yaml.Strict()); err != nil {
+		return Fixture{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if fx.Probe == "" {
+		return Fixture{}, fmt.Errorf("%s: missing probe", path)
+	}
+	fx.Path = path
+	return fx, nil
+}
+
+// Handler serves the case's canned responses keyed by the full request URI
+// (path plus ?query), falling back to the bare path if no exact match is
+// found. Any path not present in the fixture returns 404 with an empty body,
+// which lets negative cases (and unmet require:all chains) fail naturally.
+func (c Case) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.RequestURI() // path plus ?query, matching how probes specify paths
+		resp, ok := c.Responses[key]
+		if !ok {
+			resp, ok = c.Responses[r.URL.Path]
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if resp.Method != "" && !strings.EqualFold(r.Method, resp.Method) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		for k, v := range resp.Headers {
+			w.Header().Set(k, v)
+		}
+		status := resp.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, resp.Body)
+	})
+}
+
+// Run serves the case from a local server, calls scan with the server's URL
+// and checks the results against the case. It returns nil when the case
+// passes and an error describing the mismatch otherwise.
+func (c Case) Run(scan func(target string) []types.Result) error {
+	if len(c.Responses) == 0 {
+		return fmt.Errorf("case has no responses")
+	}
+
+	server := httptest.NewServer(c.Handler())
+	defer server.Close()
+
+	results := scan(server.URL)
+	matched := len(results) > 0
+	switch {
+	case c.Match && !matched:
+		return fmt.Errorf("expected a match, got none")
+	case !c.Match && matched:
+		return fmt.Errorf("expected no match, got a match")
+	case matched && c.Auth != "" && results[0].Auth != c.Auth:
+		return fmt.Errorf("expected auth %q, got %q", c.Auth, results[0].Auth)
+	}
+	return nil
+}
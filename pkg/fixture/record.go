@@ -0,0 +1,167 @@
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// DefaultMaxBody is the body size above which recorded bodies are trimmed.
+const DefaultMaxBody = 4096
+
+// keepItems is how many elements of each JSON array a trimmed body keeps.
+const keepItems = 3
+
+// Redacted replaces every secret scrubbed from a recorded response.
+const Redacted = "REDACTED"
+
+// secretPatterns match credentials that must never land in a fixture. Each
+// keeps its first group, so the redacted text still shows what was there.
+var secretPatterns = []*regexp.Regexp{
+	// JSON string values of credential keys: "api_key": "...", "access_token": "...".
+	// Bare "token" and "secret" only match whole, so model metadata such as
+	// "eos_token" or "bos_token" is kept.
+	regexp.MustCompile(`(?i)("(?:[a-z0-9_-]*(?:api[_-]?key|access[_-]?token|refresh[_-]?token|id[_-]?token|auth[_-]?token|session[_-]?token|bearer[_-]?token|client[_-]?secret|secret[_-]?key|private[_-]?key|password|passwd|credential)s?|token|secret)"\s*:\s*")(?:[^"\\]|\\.)*"`),
+	regexp.MustCompile(`(?i)(bearer\s+)[a-z0-9._~+/=-]{8,}`),
+	// Basic credentials after an Authorization key, or as a whole header value.
+	regexp.MustCompile(`(?i)(authorization["']?\s*[:=]\s*["']?basic\s+)[a-z0-9+/]+=*`),
+	regexp.MustCompile(`(?i)^(basic\s+)[a-z0-9+/]+=*$`),
+	regexp.MustCompile(`(sk-(?:ant-|proj-)?)[A-Za-z0-9_-]{16,}`),
+	regexp.MustCompile(`(hf_)[A-Za-z0-9]{20,}`),
+	regexp.MustCompile(`(gh[pousr]_)[A-Za-z0-9]{20,}`),
+	regexp.MustCompile(`(AKIA)[0-9A-Z]{16}`),
+	regexp.MustCompile(`()eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+}
+
+// Scrub replaces API keys, bearer and basic credentials, JWTs and the values
+// of credential JSON keys in s with Redacted, leaving the rest of s
+// byte-for-byte intact.
+func Scrub(s string) string {
+	for _, re := range secretPatterns {
+		s = re.ReplaceAllStringFunc(s, func(m string) string {
+			prefix := re.FindStringSubmatch(m)[1]
+			if strings.HasSuffix(prefix, `"`) {
+				return prefix + Redacted + `"`
+			}
+			return prefix + Redacted
+		})
+	}
+	return s
+}
+
+// Trim shortens a body larger than max bytes. JSON keeps the first few
+// elements of every array, so it stays parseable for models extraction;
+// anything else is cut at max bytes. Smaller bodies are returned unchanged.
+func Trim(body []byte, max int) []byte {
+	if max <= 0 || len(body) <= max {
+		return body
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err == nil && !dec.More() {
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(trimJSON(v)); err == nil {
+			return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+		}
+	}
+
+	cut := max
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return body[:cut]
+}
+
+func trimJSON(v any) any {
+	switch v := v.(type) {
+	case []any:
+		if len(v) > keepItems {
+			v = v[:keepItems]
+		}
+		for i := range v {
+			v[i] = trimJSON(v[i])
+		}
+		return v
+	case map[string]any:
+		for k := range v {
+			v[k] = trimJSON(v[k])
+		}
+		return v
+	}
+	return v
+}
+
+// RecordedHeaders returns the response headers worth recording for p:
+// Content-Type and every header its match rules inspect.
+func RecordedHeaders(p *types.Probe) []string {
+	headers := []string{"Content-Type"}
+	for _, req := range p.Requests {
+		for _, r := range req.RawMatch {
+			if h := http.CanonicalHeaderKey(r.Header); h != "" && !slices.Contains(headers, h) {
+				headers = append(headers, h)
+			}
+		}
+	}
+	return headers
+}
+
+// NewResponse builds a fixture response from a live one, keeping only the
+// named headers, trimming the body to about maxBody bytes and scrubbing
+// secrets from both. Method is recorded unless it is GET, so a probe whose
+// method regresses stops receiving the response.
+//
+// The body is scrubbed before it is trimmed, so a secret cut short of a
+// pattern's minimum length is still caught, and again after, since
+// re-encoding trimmed JSON can unescape one.
+func NewResponse(method string, status int, header http.Header, body []byte, headers []string, maxBody int) Response {
+	resp := Response{Status: status, Body: Scrub(string(Trim([]byte(Scrub(string(body))), maxBody)))}
+	if !strings.EqualFold(method, http.MethodGet) {
+		resp.Method = strings.ToUpper(method)
+	}
+	for _, name := range headers {
+		// Rules read the first value, as header.Get does.
+		if v := header.Get(name); v != "" {
+			if resp.Headers == nil {
+				resp.Headers = make(map[string]string)
+			}
+			resp.Headers[name] = Scrub(v)
+		}
+	}
+	return resp
+}
+
+var marshalOptions = []yaml.EncodeOption{
+	yaml.Indent(2),
+	yaml.IndentSequence(true),
+	yaml.UseLiteralStyleIfMultiline(true),
+	yaml.UseSingleQuote(true),
+}
+
+// Marshal encodes fx in the layout of the hand-written fixtures.
+func Marshal(fx Fixture) ([]byte, error) {
+	return yaml.MarshalWithOptions(fx, marshalOptions...)
+}
+
+// MarshalCase encodes c as an item of a fixture's cases list, indented as
+// Marshal indents it, ready to be appended to a fixture file.
+func MarshalCase(c Case) ([]byte, error) {
+	data, err := yaml.MarshalWithOptions(struct {
+		Cases []Case `yaml:"cases"`
+	}{[]Case{c}}, marshalOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimPrefix(data, []byte("cases:\n")), nil
+}
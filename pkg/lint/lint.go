@@ -0,0 +1,263 @@
+// Package lint analyses a probe set as a whole for probes that can never be
+// told apart: duplicate names, identical or shadowing match sets, and
+// category and port_hint values that disagree with the rest of the set.
+package lint
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Checks.
+const (
+	CheckDuplicateName    = "duplicate-name"
+	CheckIdenticalProbes  = "identical-probes"
+	CheckIdenticalRequest = "identical-request"
+	CheckShadowing        = "shadowing"
+	CheckCategory         = "category"
+	CheckPortHint         = "port-hint"
+)
+
+// Finding is one problem in the probe set.
+type Finding struct {
+	Severity string   `json:"severity"`
+	Check    string   `json:"check"`
+	Probes   []string `json:"probes"`
+	Message  string   `json:"message"`
+}
+
+// Lint analyses probes, which should have extends resolved and may contain
+// several probes with the same name.
+func Lint(probes []*types.Probe) []Finding {
+	var findings []Finding
+	findings = append(findings, duplicateNames(probes)...)
+	findings = append(findings, overlaps(probes)...)
+	for _, p := range probes {
+		findings = append(findings, consistency(p)...)
+	}
+
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		return cmp.Or(
+			cmp.Compare(severityRank(a.Severity), severityRank(b.Severity)),
+			cmp.Compare(a.Check, b.Check),
+			slices.Compare(a.Probes, b.Probes),
+		)
+	})
+	return findings
+}
+
+// HasErrors reports whether any finding is an error.
+func HasErrors(findings []Finding) bool {
+	return slices.ContainsFunc(findings, func(f Finding) bool { return f.Severity == SeverityError })
+}
+
+func severityRank(s string) int {
+	if s == SeverityError {
+		return 0
+	}
+	return 1
+}
+
+func duplicateNames(probes []*types.Probe) []Finding {
+	sources := make(map[string][]string)
+	for _, p := range probes {
+		sources[p.Name] = append(sources[p.Name], cmp.Or(p.Source, "?"))
+	}
+	var findings []Finding
+	for _, name := range slices.Sorted(maps.Keys(sources)) {
+		if files := sources[name]; len(files) > 1 {
+			findings = append(findings, Finding{
+				Severity: SeverityError,
+				Check:    CheckDuplicateName,
+				Probes:   []string{name},
+				Message:  fmt.Sprintf("probe name %s is defined %d times (%s); only the last is loaded", name, len(files), strings.Join(files, ", ")),
+			})
+		}
+	}
+	return findings
+}
+
+func overlaps(probes []*types.Probe) []Finding {
+	var findings []Finding
+	for i, a := range probes {
+		for _, b := range probes[i+1:] {
+			if a.Name == b.Name {
+				continue
+			}
+			findings = append(findings, comparePair(a, b)...)
+		}
+	}
+	return findings
+}
+
+// comparePair reports a pair whose match sets are identical, or where the
+// more specific probe matches every response the other matches. Otherwise it
+// reports requests the two share verbatim.
+func comparePair(a, b *types.Probe) []Finding {
+	aCoversB, bCoversA := covers(a, b), covers(b, a)
+	names := []string{a.Name, b.Name}
+	switch {
+	case aCoversB && bCoversA:
+		return []Finding{{
+			Severity: SeverityWarning,
+			Check:    CheckIdenticalProbes,
+			Probes:   names,
+			Message:  fmt.Sprintf("%s and %s match exactly the same responses and can never be told apart", a.Name, b.Name),
+		}}
+	case aCoversB && a.GetSpecificity() > b.GetSpecificity():
+		return []Finding{shadowing(a, b)}
+	case bCoversA && b.GetSpecificity() > a.GetSpecificity():
+		return []Finding{shadowing(b, a)}
+	}
+
+	var findings []Finding
+	for i, ra := range a.Requests {
+		for j, rb := range b.Requests {
+			if requestSignature(ra) == requestSignature(rb) {
+				findings = append(findings, Finding{
+					Severity: SeverityWarning,
+					Check:    CheckIdenticalRequest,
+					Probes:   names,
+					Message:  fmt.Sprintf("request %d of %s and request %d of %s (%s %s) are identical, so neither distinguishes the two services", i, a.Name, j, b.Name, ra.Method, displayPath(ra.Path)),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+func shadowing(specific, generic *types.Probe) Finding {
+	return Finding{
+		Severity: SeverityWarning,
+		Check:    CheckShadowing,
+		Probes:   []string{specific.Name, generic.Name},
+		Message: fmt.Sprintf("%s (specificity %d) matches every response %s (specificity %d) matches, so every %s target is also reported as %s",
+			specific.Name, specific.GetSpecificity(), generic.Name, generic.GetSpecificity(), generic.Name, specific.Name),
+	}
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "<target>"
+	}
+	return path
+}
+
+// covers reports whether p matches whenever q does. It compares requests
+// syntactically, so it can miss overlaps but never invents one.
+func covers(p, q *types.Probe) bool {
+	if len(p.Requests) == 0 || len(q.Requests) == 0 {
+		return false
+	}
+	// Each scenario is a set of q's requests known to match together.
+	var scenarios [][]types.Request
+	if q.RequiresAll() {
+		scenarios = [][]types.Request{q.Requests}
+	} else {
+		for _, r := range q.Requests {
+			scenarios = append(scenarios, []types.Request{r})
+		}
+	}
+
+	for _, matched := range scenarios {
+		coveredBy := func(pr types.Request) bool {
+			return slices.ContainsFunc(matched, func(qr types.Request) bool { return requestCovers(pr, qr) })
+		}
+		var ok bool
+		if p.RequiresAll() {
+			ok = !slices.ContainsFunc(p.Requests, func(pr types.Request) bool { return !coveredBy(pr) })
+		} else {
+			ok = slices.ContainsFunc(p.Requests, coveredBy)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// requestCovers reports whether a matches every response b matches: both
+// send the same request and a's rules are a subset of b's.
+func requestCovers(a, b types.Request) bool {
+	if requestShape(a) != requestShape(b) {
+		return false
+	}
+	bRules := ruleSet(b)
+	for rule := range ruleSet(a) {
+		if !bRules[rule] {
+			return false
+		}
+	}
+	return true
+}
+
+// requestShape identifies what is sent, independent of how it is matched.
+func requestShape(r types.Request) string {
+	headers := make([]string, 0, len(r.Headers))
+	for k, v := range r.Headers {
+		headers = append(headers, strings.ToLower(k)+"="+v)
+	}
+	slices.Sort(headers)
+	method := cmp.Or(strings.ToUpper(r.Method), "GET")
+	typ := cmp.Or(r.Type, types.RequestTypeHTTP)
+	return strings.Join([]string{typ, method, r.Path, r.Body, strings.Join(headers, "\n")}, "\x00")
+}
+
+func ruleSet(r types.Request) map[string]bool {
+	set := make(map[string]bool, len(r.RawMatch))
+	for _, raw := range r.RawMatch {
+		value := fmt.Sprint(raw.Value)
+		if raw.Type == "content-type" {
+			value = strings.ToLower(value)
+		}
+		set[fmt.Sprintf("%s|%t|%s|%s", raw.Type, raw.Not, strings.ToLower(raw.Header), value)] = true
+	}
+	return set
+}
+
+func requestSignature(r types.Request) string {
+	rules := slices.Sorted(maps.Keys(ruleSet(r)))
+	return requestShape(r) + "\x00" + strings.Join(rules, "\x00")
+}
+
+// consistency checks a probe's category, specificity and port_hint against
+// what the category implies.
+func consistency(p *types.Probe) []Finding {
+	var findings []Finding
+	add := func(check, format string, args ...any) {
+		findings = append(findings, Finding{Severity: SeverityWarning, Check: check, Probes: []string{p.Name}, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch {
+	case p.Category == "":
+		add(CheckCategory, "%s has no category", p.Name)
+	case !slices.Contains(types.Categories, p.Category):
+		add(CheckCategory, "%s has category %q; known categories are %s", p.Name, p.Category, strings.Join(types.Categories, ", "))
+	case p.Category == types.CategoryGeneric && p.GetSpecificity() > types.SpecificityLow:
+		add(CheckCategory, "%s is a generic fallback but has specificity %d (generic probes should be at most %d)", p.Name, p.GetSpecificity(), types.SpecificityLow)
+	case p.Category != types.CategoryGeneric && p.GetSpecificity() <= types.SpecificityGeneric:
+		add(CheckCategory, "%s has fallback specificity %d but category %s (use category generic)", p.Name, p.GetSpecificity(), p.Category)
+	}
+
+	switch {
+	case p.PortHint < 0 || p.PortHint > 65535:
+		add(CheckPortHint, "%s has port_hint %d, outside 1-65535", p.Name, p.PortHint)
+	case p.Category == types.CategoryCloudManaged && p.PortHint != 0 && p.PortHint != 443:
+		add(CheckPortHint, "%s is cloud-managed but hints port %d (hosted APIs are served on 443)", p.Name, p.PortHint)
+	case p.Category == types.CategoryGeneric && p.PortHint != 0:
+		add(CheckPortHint, "%s is a generic fallback but hints port %d", p.Name, p.PortHint)
+	case p.Category != types.CategoryGeneric && p.PortHint == 0:
+		add(CheckPortHint, "%s has no port_hint", p.Name)
+	}
+	return findings
+}
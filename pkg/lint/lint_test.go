@@ -0,0 +1,135 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/probe"
+	"github.com/praetorian-inc/julius/pkg/rules"
+	"github.com/praetorian-inc/julius/pkg/types"
+	"github.com/praetorian-inc/julius/probes"
+)
+
+var (
+	status200   = rules.RawRule{Type: "status", Value: 200}
+	jsonType    = rules.RawRule{Type: "content-type", Value: "application/json"}
+	hasData     = rules.RawRule{Type: "body.contains", Value: `"data"`}
+	hasObject   = rules.RawRule{Type: "body.contains", Value: `"object"`}
+	uvicornHead = rules.RawRule{Type: "header.contains", Header: "Server", Value: "uvicorn"}
+)
+
+func req(path string, match ...rules.RawRule) types.Request {
+	return types.Request{Type: "http", Method: "GET", Path: path, RawMatch: match}
+}
+
+func newProbe(name string, specificity int, requests ...types.Request) *types.Probe {
+	return &types.Probe{Name: name, Category: types.CategorySelfHosted, PortHint: 8000, Specificity: specificity, Requests: requests}
+}
+
+func checks(findings []Finding) map[string][]string {
+	got := map[string][]string{}
+	for _, f := range findings {
+		got[f.Check] = append(got[f.Check], f.Message)
+	}
+	return got
+}
+
+func TestLint_DuplicateName(t *testing.T) {
+	a := newProbe("vllm", 75, req("/v1/models", status200, uvicornHead))
+	b := newProbe("vllm", 75, req("/version", status200))
+	a.Source, b.Source = "vllm.yaml", "vllm-copy.yaml"
+
+	findings := Lint([]*types.Probe{a, b})
+	require.NotEmpty(t, findings)
+	assert.Equal(t, Finding{
+		Severity: SeverityError,
+		Check:    CheckDuplicateName,
+		Probes:   []string{"vllm"},
+		Message:  "probe name vllm is defined 2 times (vllm.yaml, vllm-copy.yaml); only the last is loaded",
+	}, findings[0], "errors sort first")
+	assert.True(t, HasErrors(findings))
+}
+
+func TestLint_IdenticalProbes(t *testing.T) {
+	// Same rules in a different order, and a header case difference.
+	a := newProbe("a", 80, req("/v1/models", status200, hasData))
+	b := newProbe("b", 80, req("/v1/models", hasData, status200))
+	a.Requests[0].Headers = map[string]string{"Accept": "application/json"}
+	b.Requests[0].Headers = map[string]string{"accept": "application/json"}
+
+	got := checks(Lint([]*types.Probe{a, b}))
+	assert.Equal(t, []string{"a and b match exactly the same responses and can never be told apart"}, got[CheckIdenticalProbes])
+	assert.Empty(t, got[CheckIdenticalRequest], "the pair is reported once")
+}
+
+func TestLint_IdenticalRequest(t *testing.T) {
+	a := newProbe("a", 80, req("/v1/models", status200, hasData), req("/a", status200))
+	b := newProbe("b", 80, req("/v1/models", status200, hasData), req("/b", status200))
+
+	got := checks(Lint([]*types.Probe{a, b}))
+	assert.Equal(t, []string{"request 0 of a and request 0 of b (GET /v1/models) are identical, so neither distinguishes the two services"}, got[CheckIdenticalRequest])
+	assert.Empty(t, got[CheckIdenticalProbes])
+}
+
+func TestLint_Shadowing(t *testing.T) {
+	generic := newProbe("openai-compatible", 1, req("/v1/models", status200, jsonType, hasData, hasObject))
+	generic.Category, generic.PortHint = types.CategoryGeneric, 0
+
+	// Fewer rules on the same request: matches everything the generic probe does.
+	sloppy := newProbe("sloppy", 90, req("/v1/models", status200, hasData))
+	// More rules: strictly narrower, which is how a specific probe should look.
+	careful := newProbe("careful", 90, req("/v1/models", status200, jsonType, hasData, hasObject, uvicornHead))
+
+	got := checks(Lint([]*types.Probe{generic, sloppy, careful}))
+	assert.Equal(t, []string{
+		"sloppy (specificity 90) matches every response openai-compatible (specificity 1) matches, so every openai-compatible target is also reported as sloppy",
+	}, got[CheckShadowing])
+}
+
+func TestLint_CoversRequireAll(t *testing.T) {
+	models := req("/v1/models", status200, hasData)
+	version := req("/version", status200)
+
+	all := newProbe("all", 75, models, version)
+	all.Require = types.RequireAll
+	anyProbe := newProbe("any", 75, req("/v1/models", status200, hasData, uvicornHead))
+
+	assert.True(t, covers(newProbe("x", 1, models), all), "an any-probe covers an all-probe sharing one request")
+	assert.False(t, covers(all, anyProbe), "an all-probe needs every request matched")
+	assert.True(t, covers(all, func() *types.Probe {
+		p := newProbe("all2", 75, req("/v1/models", status200, hasData, uvicornHead), req("/version", status200, jsonType))
+		p.Require = types.RequireAll
+		return p
+	}()))
+}
+
+func TestLint_Consistency(t *testing.T) {
+	cloud := newProbe("cloud", 85, req("/a", status200))
+	cloud.Category, cloud.PortHint = types.CategoryCloudManaged, 8443
+	generic := newProbe("fallback", 60, req("/b", status200))
+	generic.Category, generic.PortHint = types.CategoryGeneric, 0
+	unknown := newProbe("weird", 80, req("/c", status200))
+	unknown.Category = "inference"
+	lowSpec := newProbe("low", 1, req("/d", status200))
+	noPort := newProbe("noport", 80, req("/e", status200))
+	noPort.PortHint = 0
+
+	got := checks(Lint([]*types.Probe{cloud, generic, unknown, lowSpec, noPort}))
+	assert.ElementsMatch(t, []string{
+		"fallback is a generic fallback but has specificity 60 (generic probes should be at most 25)",
+		`weird has category "inference"; known categories are self-hosted, gateway, mcp, rag-orchestration, cloud-managed, generic`,
+		"low has fallback specificity 1 but category self-hosted (use category generic)",
+	}, got[CheckCategory])
+	assert.ElementsMatch(t, []string{
+		"cloud is cloud-managed but hints port 8443 (hosted APIs are served on 443)",
+		"noport has no port_hint",
+	}, got[CheckPortHint])
+}
+
+func TestLint_EmbeddedProbesAreClean(t *testing.T) {
+	loaded, err := probe.LoadFiles(probe.Source{Name: "embedded", FS: probes.EmbeddedProbes, Dir: "."})
+	require.NoError(t, err)
+	assert.Empty(t, Lint(loaded))
+}
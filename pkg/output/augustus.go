@@ -0,0 +1,113 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// AugustusIndexFile is the manifest written next to the generator configs.
+const AugustusIndexFile = "index.json"
+
+// AugustusIndexEntry describes one generator config file in the manifest.
+type AugustusIndexEntry struct {
+	File      string `json:"file"`
+	Target    string `json:"target"`
+	Service   string `json:"service"`
+	Model     string `json:"model,omitempty"`
+	Generator string `json:"generator"`
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// WriteGeneratorConfigs writes every generator config in results to its own
+// file in dir, named <target>_<service>[_<model>].<format>, plus an index.json
+// manifest so each config can be fed to Augustus one run at a time. format is
+// "yaml" or "json".
+func WriteGeneratorConfigs(dir, format string, results []types.Result) error {
+	if format != "yaml" && format != "json" {
+		return fmt.Errorf("unknown generator config format: %s", format)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+
+	index := []AugustusIndexEntry{}
+	used := make(map[string]bool)
+
+	for _, result := range results {
+		target := result.BaseTarget()
+		for _, cfg := range result.GeneratorConfigs {
+			name := uniqueFileName(used, generatorConfigFileName(target, result.Service, cfg.Model), format)
+
+			data, err := marshalGeneratorConfig(cfg, format)
+			if err != nil {
+				return fmt.Errorf("encoding %s: %w", name, err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+				return fmt.Errorf("writing %s: %w", name, err)
+			}
+
+			index = append(index, AugustusIndexEntry{
+				File:      name,
+				Target:    target,
+				Service:   result.Service,
+				Model:     cfg.Model,
+				Generator: cfg.Type,
+			})
+		}
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", AugustusIndexFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, AugustusIndexFile), append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing %s: %w", AugustusIndexFile, err)
+	}
+	return nil
+}
+
+func marshalGeneratorConfig(cfg types.GeneratorConfig, format string) ([]byte, error) {
+	if format == "json" {
+		data, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+	return yaml.Marshal(cfg)
+}
+
+// generatorConfigFileName builds a filesystem-safe base name from the target
+// (scheme dropped), service and model.
+func generatorConfigFileName(target, service, model string) string {
+	if _, rest, ok := strings.Cut(target, "://"); ok {
+		target = rest
+	}
+	parts := []string{target, service}
+	if model != "" {
+		parts = append(parts, model)
+	}
+	for i, p := range parts {
+		parts[i] = strings.Trim(unsafeFileChars.ReplaceAllString(p, "-"), "-.")
+	}
+	return strings.Join(parts, "_")
+}
+
+// uniqueFileName appends a numeric suffix when two configs sanitize to the
+// same name (e.g. models differing only in characters that were replaced).
+func uniqueFileName(used map[string]bool, base, ext string) string {
+	name := base + "." + ext
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s-%d.%s", base, i, ext)
+	}
+	used[name] = true
+	return name
+}
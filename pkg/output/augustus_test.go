@@ -0,0 +1,83 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+func TestWriteGeneratorConfigs(t *testing.T) {
+	dir := t.TempDir()
+	results := []types.Result{
+		{
+			Target:         "http://10.0.0.1:11434/api/tags",
+			MatchedRequest: "/api/tags",
+			Service:        "ollama",
+			GeneratorConfigs: []types.GeneratorConfig{
+				{Type: "ollama", Endpoint: "http://10.0.0.1:11434", Model: "llama3.2:latest"},
+				{Type: "ollama", Endpoint: "http://10.0.0.1:11434", Model: "llama3.2/latest"},
+			},
+		},
+		{
+			Target:  "https://mcp.example.com/mcp",
+			Service: "mcp-server",
+			GeneratorConfigs: []types.GeneratorConfig{
+				{Type: "mcp", Endpoint: "https://mcp.example.com/mcp", Extra: map[string]any{"transport": "auto"}},
+			},
+		},
+		{Target: "https://no-augustus.example.com", Service: "gradio"},
+	}
+
+	require.NoError(t, WriteGeneratorConfigs(dir, "yaml", results))
+
+	indexData, err := os.ReadFile(filepath.Join(dir, AugustusIndexFile))
+	require.NoError(t, err)
+	var index []AugustusIndexEntry
+	require.NoError(t, json.Unmarshal(indexData, &index))
+
+	assert.Equal(t, []AugustusIndexEntry{
+		{File: "10.0.0.1-11434_ollama_llama3.2-latest.yaml", Target: "http://10.0.0.1:11434", Service: "ollama", Model: "llama3.2:latest", Generator: "ollama"},
+		{File: "10.0.0.1-11434_ollama_llama3.2-latest-2.yaml", Target: "http://10.0.0.1:11434", Service: "ollama", Model: "llama3.2/latest", Generator: "ollama"},
+		{File: "mcp.example.com-mcp_mcp-server.yaml", Target: "https://mcp.example.com/mcp", Service: "mcp-server", Generator: "mcp"},
+	}, index)
+
+	data, err := os.ReadFile(filepath.Join(dir, index[0].File))
+	require.NoError(t, err)
+	var cfg types.GeneratorConfig
+	require.NoError(t, yaml.Unmarshal(data, &cfg))
+	assert.Equal(t, results[0].GeneratorConfigs[0], cfg)
+}
+
+func TestWriteGeneratorConfigs_JSON(t *testing.T) {
+	dir := t.TempDir()
+	results := []types.Result{{
+		Target:           "https://api.example.com",
+		Service:          "openai-compatible",
+		GeneratorConfigs: []types.GeneratorConfig{{Type: "openai", Endpoint: "https://api.example.com", Model: "gpt-4o"}},
+	}}
+
+	require.NoError(t, WriteGeneratorConfigs(dir, "json", results))
+
+	data, err := os.ReadFile(filepath.Join(dir, "api.example.com_openai-compatible_gpt-4o.json"))
+	require.NoError(t, err)
+	var cfg types.GeneratorConfig
+	require.NoError(t, json.Unmarshal(data, &cfg))
+	assert.Equal(t, "gpt-4o", cfg.Model)
+}
+
+func TestWriteGeneratorConfigs_EmptyAndInvalidFormat(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, WriteGeneratorConfigs(dir, "yaml", nil))
+	indexData, err := os.ReadFile(filepath.Join(dir, AugustusIndexFile))
+	require.NoError(t, err)
+	assert.JSONEq(t, "[]", string(indexData))
+
+	assert.Error(t, WriteGeneratorConfigs(dir, "toml", nil))
+}
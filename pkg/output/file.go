@@ -0,0 +1,56 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// Formats lists every format accepted by NewWriter.
+var Formats = []string{"table", "json", "jsonl", "html"}
+
+// FileOutput is a single --output-file destination: results rendered in
+// Format and written to Path.
+type FileOutput struct {
+	Format string
+	Path   string
+}
+
+// ParseFileOutput parses a "format:path" pair. Only the first colon separates
+// the two, so paths may themselves contain colons.
+func ParseFileOutput(spec string) (FileOutput, error) {
+	format, path, ok := strings.Cut(spec, ":")
+	if !ok || format == "" || path == "" {
+		return FileOutput{}, fmt.Errorf("invalid output file %q (expected \"format:path\")", spec)
+	}
+	if !slices.Contains(Formats, format) {
+		return FileOutput{}, fmt.Errorf("invalid output file %q: unknown format: %s", spec, format)
+	}
+	return FileOutput{Format: format, Path: path}, nil
+}
+
+// WriteFile renders results into the destination file, creating or
+// truncating it.
+func (fo FileOutput) WriteFile(results []types.Result, opts ...WriterOption) (err error) {
+	f, err := os.Create(fo.Path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", fo.Path, err)
+	}
+	defer func() {
+		if cerr := f.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("closing %s: %w", fo.Path, cerr)
+		}
+	}()
+
+	writer, err := NewWriter(fo.Format, f, opts...)
+	if err != nil {
+		return err
+	}
+	if err := writer.Write(results); err != nil {
+		return fmt.Errorf("writing %s: %w", fo.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package output
+
+import (
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join": strings.Join,
+	"auth": authLabel,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Julius scan report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border: 1px solid #ccc; padding: 0.4em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+td.error { color: #b00; }
+</style>
+</head>
+<body>
+<h1>Julius scan report</h1>
+{{- if .}}
+<table>
+<thead>
+<tr><th>Target</th><th>Service</th><th>Specificity</th><th>Category</th><th>Auth</th><th>Models</th><th>Error</th></tr>
+</thead>
+<tbody>
+{{- range .}}
+<tr><td>{{.Target}}</td><td>{{.Service}}</td><td>{{.Specificity}}</td><td>{{.Category}}</td><td>{{auth .}}</td><td>{{join .Models ", "}}</td><td class="error">{{.Error}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<p>No matches found</p>
+{{- end}}
+</body>
+</html>
+`))
+
+type HTMLWriter struct {
+	writer io.Writer
+}
+
+func NewHTMLWriter(w io.Writer) types.OutputWriter {
+	return &HTMLWriter{writer: w}
+}
+
+func (hw *HTMLWriter) Write(results []types.Result) error {
+	return htmlReport.Execute(hw.writer, results)
+}
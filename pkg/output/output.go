@@ -25,7 +25,7 @@ func (tw *TableWriter) Write(results []types.Result) error {
 	}
 
 	table := tablewriter.NewWriter(tw.writer)
-	table.SetHeader([]string{"TARGET", "SERVICE", "SPECIFICITY", "CATEGORY", "MODELS", "ERROR"})
+	table.SetHeader([]string{"TARGET", "SERVICE", "SPECIFICITY", "CATEGORY", "AUTH", "MODELS", "ERROR"})
 	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
 
@@ -37,6 +37,7 @@ func (tw *TableWriter) Write(results []types.Result) error {
 			result.Service,
 			fmt.Sprintf("%d", result.Specificity),
 			result.Category,
+			authLabel(result),
 			models,
 			result.Error,
 		})
@@ -46,14 +47,30 @@ func (tw *TableWriter) Write(results []types.Result) error {
 	return nil
 }
 
+// authLabel is the AUTH cell: the posture, marked when --verify-inference
+// proved it with a served completion.
+func authLabel(r types.Result) string {
+	if r.Inference != nil && r.Inference.Verified {
+		return r.Auth + " (verified)"
+	}
+	return r.Auth
+}
+
 type JSONWriter struct {
-	writer io.Writer
+	writer   io.Writer
+	metadata *types.ScanMetadata
 }
 
 func NewJSONWriter(w io.Writer) types.OutputWriter {
 	return &JSONWriter{writer: w}
 }
 
+// NewJSONEnvelopeWriter writes a types.ScanReport object carrying the scan
+// metadata instead of a bare results array.
+func NewJSONEnvelopeWriter(w io.Writer, metadata *types.ScanMetadata) types.OutputWriter {
+	return &JSONWriter{writer: w, metadata: metadata}
+}
+
 func (jw *JSONWriter) Write(results []types.Result) error {
 	if len(results) == 0 {
 		results = []types.Result{}
@@ -61,6 +78,9 @@ func (jw *JSONWriter) Write(results []types.Result) error {
 
 	encoder := json.NewEncoder(jw.writer)
 	encoder.SetIndent("", "  ")
+	if jw.metadata != nil {
+		return encoder.Encode(types.ScanReport{Metadata: *jw.metadata, Results: results})
+	}
 	return encoder.Encode(results)
 }
 
@@ -82,14 +102,38 @@ func (jw *JSONLWriter) Write(results []types.Result) error {
 	return nil
 }
 
-func NewWriter(format string, w io.Writer) (types.OutputWriter, error) {
+type writerOptions struct {
+	metadata *types.ScanMetadata
+}
+
+type WriterOption func(*writerOptions)
+
+// WithMetadata wraps json output in a types.ScanReport envelope. Formats
+// without a document root to carry it (table, jsonl, html) ignore it.
+func WithMetadata(metadata *types.ScanMetadata) WriterOption {
+	return func(o *writerOptions) {
+		o.metadata = metadata
+	}
+}
+
+func NewWriter(format string, w io.Writer, opts ...WriterOption) (types.OutputWriter, error) {
+	var o writerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	switch format {
 	case "table":
 		return NewTableWriter(w), nil
 	case "json":
+		if o.metadata != nil {
+			return NewJSONEnvelopeWriter(w, o.metadata), nil
+		}
 		return NewJSONWriter(w), nil
 	case "jsonl":
 		return NewJSONLWriter(w), nil
+	case "html":
+		return NewHTMLWriter(w), nil
 	default:
 		return nil, fmt.Errorf("unknown format: %s", format)
 	}
@@ -3,8 +3,11 @@ package output
 import (
 	"bytes"
 	"encoding/json"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/praetorian-inc/julius/pkg/types"
 	"github.com/stretchr/testify/assert"
@@ -373,3 +376,158 @@ func TestTableWriterModelsAndError(t *testing.T) {
 		})
 	}
 }
+
+func TestNewWriter_HTML(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer, err := NewWriter("html", buf)
+	require.NoError(t, err, "NewWriter should not fail")
+	require.NotNil(t, writer, "NewWriter should not return nil")
+
+	results := []types.Result{
+		{
+			Target:      "http://10.0.0.1:11434",
+			Service:     "ollama",
+			Category:    "self-hosted",
+			Specificity: 100,
+			Models:      []string{"llama3.2:latest", "qwen2.5"},
+			Error:       "<script>alert(1)</script>",
+		},
+	}
+	require.NoError(t, writer.Write(results))
+
+	output := buf.String()
+	assert.Contains(t, output, "<table>")
+	assert.Contains(t, output, "http://10.0.0.1:11434")
+	assert.Contains(t, output, "llama3.2:latest, qwen2.5")
+	assert.NotContains(t, output, "<script>", "result fields must be HTML-escaped")
+}
+
+func TestHTMLWriter_WriteEmptyResults(t *testing.T) {
+	buf := &bytes.Buffer{}
+	require.NoError(t, NewHTMLWriter(buf).Write(nil))
+	assert.Contains(t, buf.String(), "No matches found")
+	assert.NotContains(t, buf.String(), "<table>")
+}
+
+func TestParseFileOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    FileOutput
+		wantErr bool
+	}{
+		{"jsonl", "jsonl:results.jsonl", FileOutput{Format: "jsonl", Path: "results.jsonl"}, false},
+		{"html", "html:out/report.html", FileOutput{Format: "html", Path: "out/report.html"}, false},
+		{"colon in path", `json:C:\scans\out.json`, FileOutput{Format: "json", Path: `C:\scans\out.json`}, false},
+		{"missing path", "json:", FileOutput{}, true},
+		{"missing format", ":out.json", FileOutput{}, true},
+		{"no separator", "results.json", FileOutput{}, true},
+		{"unknown format", "xml:out.xml", FileOutput{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFileOutput(tt.spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFileOutput_WriteFile(t *testing.T) {
+	dir := t.TempDir()
+	results := []types.Result{
+		{Target: "https://a.example.com", Service: "vllm", Specificity: 90},
+		{Target: "https://b.example.com", Service: "ollama", Specificity: 100},
+	}
+
+	jsonlPath := filepath.Join(dir, "results.jsonl")
+	htmlPath := filepath.Join(dir, "report.html")
+	require.NoError(t, FileOutput{Format: "jsonl", Path: jsonlPath}.WriteFile(results))
+	require.NoError(t, FileOutput{Format: "html", Path: htmlPath}.WriteFile(results))
+
+	jsonl, err := os.ReadFile(jsonlPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(jsonl)), "\n")
+	require.Len(t, lines, 2)
+	var first types.Result
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "vllm", first.Service)
+
+	html, err := os.ReadFile(htmlPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(html), "https://b.example.com")
+}
+
+func TestFileOutput_WriteFileUncreatablePath(t *testing.T) {
+	err := FileOutput{Format: "json", Path: filepath.Join(t.TempDir(), "missing", "out.json")}.WriteFile(nil)
+	assert.Error(t, err)
+}
+
+func TestReadResults_RoundTrip(t *testing.T) {
+	results := []types.Result{
+		{Target: "https://a.example.com", Service: "vllm", Specificity: 90, Models: []string{"m"}},
+		{Target: "https://b.example.com", Service: "ollama", Specificity: 100},
+	}
+
+	for _, format := range []string{"json", "jsonl"} {
+		t.Run(format, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			writer, err := NewWriter(format, buf)
+			require.NoError(t, err)
+			require.NoError(t, writer.Write(results))
+
+			got, err := ReadResults(buf)
+			require.NoError(t, err)
+			assert.Equal(t, results, got)
+		})
+	}
+}
+
+func TestReadResults_EmptyAndInvalid(t *testing.T) {
+	got, err := ReadResults(strings.NewReader("  \n"))
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = ReadResults(strings.NewReader("{\"target\":\"a\"}\nnot json\n"))
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestNewWriter_JSONWithMetadata(t *testing.T) {
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	meta := &types.ScanMetadata{
+		StartedAt:    started,
+		FinishedAt:   started.Add(time.Minute),
+		Version:      "v1.0.0",
+		ProbeSetHash: "abc",
+		Probes:       []string{"ollama"},
+		TargetCount:  1,
+	}
+	results := []types.Result{{Target: "https://a", Service: "ollama", Timestamp: started.Add(time.Second)}}
+
+	buf := &bytes.Buffer{}
+	writer, err := NewWriter("json", buf, WithMetadata(meta))
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(results))
+
+	var report types.ScanReport
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, *meta, report.Metadata)
+	assert.Equal(t, results, report.Results)
+
+	got, err := ReadResults(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err, "ReadResults should accept the envelope")
+	assert.Equal(t, results, got)
+}
+
+func TestNewWriter_MetadataIgnoredByJSONL(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer, err := NewWriter("jsonl", buf, WithMetadata(&types.ScanMetadata{Version: "v1"}))
+	require.NoError(t, err)
+	require.NoError(t, writer.Write([]types.Result{{Target: "https://a"}}))
+	assert.NotContains(t, buf.String(), "metadata")
+}
@@ -0,0 +1,81 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// ReadResults parses results previously written by the json or jsonl writer.
+// The format is detected from the content: a JSON array is json output, a
+// single object with a "results" key is an enveloped types.ScanReport, and
+// anything else is read as one result per line.
+func ReadResults(r io.Reader) ([]types.Result, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading results: %w", err)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return []types.Result{}, nil
+	}
+
+	if trimmed[0] == '[' {
+		var results []types.Result
+		if err := json.Unmarshal(trimmed, &results); err != nil {
+			return nil, fmt.Errorf("parsing JSON results: %w", err)
+		}
+		return results, nil
+	}
+
+	if report, ok := parseReport(trimmed); ok {
+		return report.Results, nil
+	}
+
+	results := []types.Result{}
+	scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(trimmed))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var result types.Result
+		if err := json.Unmarshal(text, &result); err != nil {
+			return nil, fmt.Errorf("parsing JSONL results line %d: %w", line, err)
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading results: %w", err)
+	}
+	return results, nil
+}
+
+// parseReport decodes data as a types.ScanReport. It reports false for
+// anything else, including JSONL, which is not a single JSON value.
+func parseReport(data []byte) (types.ScanReport, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return types.ScanReport{}, false
+	}
+	if _, ok := fields["results"]; !ok {
+		return types.ScanReport{}, false
+	}
+
+	var report types.ScanReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return types.ScanReport{}, false
+	}
+	if report.Results == nil {
+		report.Results = []types.Result{}
+	}
+	return report, true
+}
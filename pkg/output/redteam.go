@@ -0,0 +1,117 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-yaml"
+	"github.com/praetorian-inc/julius/pkg/export"
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// GarakIndexFile is the manifest written next to the garak option files,
+// named apart from AugustusIndexFile so the two are never mistaken.
+const GarakIndexFile = "garak-index.json"
+
+// GarakIndexEntry describes one garak generator option file in the manifest,
+// with the --model_type and --model_name it must be run with.
+type GarakIndexEntry struct {
+	File      string `json:"file"`
+	Target    string `json:"target"`
+	Service   string `json:"service"`
+	ModelType string `json:"model_type"`
+	ModelName string `json:"model_name,omitempty"`
+}
+
+// WriteGarakConfigs converts every generator config in results to a garak
+// generator option file in dir, named like the Augustus configs, plus a
+// garak-index.json manifest. Generators garak has no equivalent for are skipped
+// with a warning.
+func WriteGarakConfigs(dir string, results []types.Result) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+
+	index := []GarakIndexEntry{}
+	used := make(map[string]bool)
+
+	for _, result := range results {
+		target := result.BaseTarget()
+		for _, cfg := range result.GeneratorConfigs {
+			garak, err := export.ToGarakConfig(cfg)
+			if errors.Is(err, export.ErrUnsupportedGenerator) {
+				slog.Warn("Skipping garak config", "target", target, "service", result.Service, "err", err)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			name := uniqueFileName(used, generatorConfigFileName(target, result.Service, cfg.Model), "json")
+			data, err := json.MarshalIndent(garak.Options, "", "  ")
+			if err != nil {
+				return fmt.Errorf("encoding %s: %w", name, err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, name), append(data, '\n'), 0o600); err != nil {
+				return fmt.Errorf("writing %s: %w", name, err)
+			}
+
+			index = append(index, GarakIndexEntry{
+				File:      name,
+				Target:    target,
+				Service:   result.Service,
+				ModelType: garak.ModelType,
+				ModelName: garak.ModelName,
+			})
+		}
+	}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", GarakIndexFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, GarakIndexFile), append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing %s: %w", GarakIndexFile, err)
+	}
+	return nil
+}
+
+// WritePromptfooProviders converts every generator config in results to a
+// promptfoo provider and writes them as a YAML list to path, ready to be
+// referenced from a promptfoo config with `providers: file://<path>`.
+// Generators promptfoo has no equivalent for are skipped with a warning.
+func WritePromptfooProviders(path string, results []types.Result) error {
+	providers := []*export.PromptfooProvider{}
+
+	for _, result := range results {
+		target := result.BaseTarget()
+		for _, cfg := range result.GeneratorConfigs {
+			label := result.Service + " @ " + target
+			if cfg.Model != "" {
+				label = result.Service + " " + cfg.Model + " @ " + target
+			}
+			provider, err := export.ToPromptfooProvider(cfg, label)
+			if errors.Is(err, export.ErrUnsupportedGenerator) {
+				slog.Warn("Skipping promptfoo provider", "target", target, "service", result.Service, "err", err)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			providers = append(providers, provider)
+		}
+	}
+
+	data, err := yaml.Marshal(providers)
+	if err != nil {
+		return fmt.Errorf("encoding promptfoo providers: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package output
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/export"
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+func redTeamResults() []types.Result {
+	return []types.Result{
+		{
+			Target:         "http://10.0.0.1:11434/api/tags",
+			MatchedRequest: "/api/tags",
+			Service:        "ollama",
+			GeneratorConfigs: []types.GeneratorConfig{
+				{Type: "ollama", Endpoint: "http://10.0.0.1:11434", Model: "llama3.2:latest"},
+			},
+		},
+		{
+			Target:  "https://mcp.example.com/mcp",
+			Service: "mcp-server",
+			GeneratorConfigs: []types.GeneratorConfig{
+				{Type: "mcp", Endpoint: "https://mcp.example.com/mcp"},
+			},
+		},
+	}
+}
+
+func TestWriteGarakConfigs(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, WriteGarakConfigs(dir, redTeamResults()))
+
+	indexData, err := os.ReadFile(filepath.Join(dir, GarakIndexFile))
+	require.NoError(t, err)
+	var index []GarakIndexEntry
+	require.NoError(t, json.Unmarshal(indexData, &index))
+
+	assert.Equal(t, []GarakIndexEntry{{
+		File:      "10.0.0.1-11434_ollama_llama3.2-latest.json",
+		Target:    "http://10.0.0.1:11434",
+		Service:   "ollama",
+		ModelType: "ollama.OllamaGeneratorChat",
+		ModelName: "llama3.2:latest",
+	}}, index, "mcp has no garak generator and is skipped")
+
+	data, err := os.ReadFile(filepath.Join(dir, index[0].File))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ollama":{"OllamaGeneratorChat":{"host":"http://10.0.0.1:11434"}}}`, string(data))
+}
+
+func TestWritePromptfooProviders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "providers.yaml")
+	require.NoError(t, WritePromptfooProviders(path, redTeamResults()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var providers []export.PromptfooProvider
+	require.NoError(t, yaml.Unmarshal(data, &providers))
+
+	require.Len(t, providers, 1)
+	assert.Equal(t, "ollama:chat:llama3.2:latest", providers[0].ID)
+	assert.Equal(t, "ollama llama3.2:latest @ http://10.0.0.1:11434", providers[0].Label)
+}
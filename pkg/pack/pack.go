@@ -0,0 +1,351 @@
+// Package pack reads, verifies and installs signed probe packs: gzipped
+// tarballs of probe YAML files plus a manifest naming each file's sha256, with
+// the manifest signed by an ed25519 key.
+package pack
+
+import (
+	"archive/tar"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ManifestFile  = "manifest.json"
+	SignatureFile = "manifest.sig" // base64 ed25519 signature over manifest.json
+
+	// MaxSize caps the compressed download and the total extracted size.
+	MaxSize = 32 << 20
+)
+
+// ErrSignature is returned when the manifest signature does not verify.
+var ErrSignature = errors.New("probe pack signature verification failed")
+
+// Manifest describes a pack. Files maps each probe file name to its hex sha256.
+type Manifest struct {
+	Version string            `json:"version"`
+	Created time.Time         `json:"created,omitzero"`
+	Files   map[string]string `json:"files"`
+}
+
+// Pack is a verified probe pack.
+type Pack struct {
+	Manifest Manifest
+	Files    map[string][]byte // probe file name -> YAML
+
+	manifest  []byte
+	signature []byte
+}
+
+// ParsePublicKey decodes a base64 ed25519 public key.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("decoding public key: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key is %d bytes, want %d", len(key), ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// Fetch downloads the pack at url and verifies it with key.
+func Fetch(ctx context.Context, client *http.Client, url string, key ed25519.PublicKey) (*Pack, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading probe pack: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading probe pack: %s", resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("downloading probe pack: %w", err)
+	}
+	if len(data) > MaxSize {
+		return nil, fmt.Errorf("probe pack exceeds %d bytes", MaxSize)
+	}
+	return Read(bytes.NewReader(data), key)
+}
+
+// Read extracts a pack from a gzipped tarball and verifies it: the manifest
+// signature must verify with key, and the probe files must be exactly the
+// ones the manifest lists, with matching hashes.
+func Read(r io.Reader, key ed25519.PublicKey) (*Pack, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading probe pack: %w", err)
+	}
+	defer gz.Close()
+
+	p := &Pack{Files: make(map[string][]byte)}
+	tr := tar.NewReader(io.LimitReader(gz, MaxSize+1))
+	total := 0
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading probe pack: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		name, err := entryName(hdr)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		if total += len(data); total > MaxSize {
+			return nil, fmt.Errorf("probe pack exceeds %d bytes", MaxSize)
+		}
+
+		switch name {
+		case ManifestFile:
+			p.manifest = data
+		case SignatureFile:
+			p.signature = data
+		default:
+			if _, dup := p.Files[name]; dup {
+				return nil, fmt.Errorf("probe pack contains %s twice", name)
+			}
+			p.Files[name] = data
+		}
+	}
+
+	if err := p.verify(key); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// entryName returns the file name of a tar entry, which must be a regular
+// file at the top level of the pack: the manifest, its signature or a probe.
+func entryName(hdr *tar.Header) (string, error) {
+	if hdr.Typeflag != tar.TypeReg {
+		return "", fmt.Errorf("probe pack entry %s is not a regular file", hdr.Name)
+	}
+	name := path.Clean(strings.TrimPrefix(hdr.Name, "./"))
+	if strings.Contains(name, "/") || name == ".." {
+		return "", fmt.Errorf("probe pack entry %s is not at the top level", hdr.Name)
+	}
+	if name != ManifestFile && name != SignatureFile && !isProbeFile(name) {
+		return "", fmt.Errorf("probe pack entry %s is not a probe file", hdr.Name)
+	}
+	return name, nil
+}
+
+func isProbeFile(name string) bool {
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml")
+}
+
+func (p *Pack) verify(key ed25519.PublicKey) error {
+	if p.manifest == nil {
+		return fmt.Errorf("probe pack has no %s", ManifestFile)
+	}
+	if p.signature == nil {
+		return fmt.Errorf("probe pack has no %s", SignatureFile)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(p.signature)))
+	if err != nil || !ed25519.Verify(key, p.manifest, sig) {
+		return ErrSignature
+	}
+
+	if err := json.Unmarshal(p.manifest, &p.Manifest); err != nil {
+		return fmt.Errorf("parsing %s: %w", ManifestFile, err)
+	}
+	if p.Manifest.Version == "" {
+		return fmt.Errorf("%s has no version", ManifestFile)
+	}
+
+	for name, want := range p.Manifest.Files {
+		data, ok := p.Files[name]
+		if !ok {
+			return fmt.Errorf("probe pack is missing %s", name)
+		}
+		if got := sha256.Sum256(data); hex.EncodeToString(got[:]) != strings.ToLower(want) {
+			return fmt.Errorf("probe pack file %s does not match its manifest hash", name)
+		}
+	}
+	for name := range p.Files {
+		if _, ok := p.Manifest.Files[name]; !ok {
+			return fmt.Errorf("probe pack file %s is not in the manifest", name)
+		}
+	}
+	return nil
+}
+
+// Write builds a signed pack from probe files, for release tooling and tests.
+func Write(w io.Writer, version string, files map[string][]byte, key ed25519.PrivateKey) error {
+	m := Manifest{Version: version, Created: time.Now().UTC(), Files: make(map[string]string, len(files))}
+	for name, data := range files {
+		sum := sha256.Sum256(data)
+		m.Files[name] = hex.EncodeToString(sum[:])
+	}
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, manifest))
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	entries := map[string][]byte{ManifestFile: manifest, SignatureFile: []byte(sig + "\n")}
+	maps.Copy(entries, files)
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		data := entries[name]
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: m.Created}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// Install replaces dir with the pack's probe files, manifest and signature.
+// The new directory is assembled beside dir and swapped in, so a failed
+// install leaves the previous pack in place. dir must be missing, empty or
+// hold an installed pack: anything else is refused rather than deleted.
+func (p *Pack) Install(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if len(entries) > 0 {
+		version, err := InstalledVersion(dir)
+		if err != nil {
+			return err
+		}
+		if version == "" {
+			return fmt.Errorf("%s is not empty and holds no probe pack; refusing to replace it", dir)
+		}
+	}
+
+	parent := filepath.Dir(dir)
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return err
+	}
+	staging, err := os.MkdirTemp(parent, ".probes-staging-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(staging)
+
+	files := map[string][]byte{ManifestFile: p.manifest, SignatureFile: p.signature}
+	maps.Copy(files, p.Files)
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(staging, name), data, 0o644); err != nil {
+			return err
+		}
+	}
+	if err := os.Chmod(staging, 0o755); err != nil {
+		return err
+	}
+
+	var backup string
+	if _, err := os.Stat(dir); err == nil {
+		backup = staging + ".old"
+		if err := os.Rename(dir, backup); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(staging, dir); err != nil {
+		if backup != "" {
+			_ = os.Rename(backup, dir)
+		}
+		return err
+	}
+	if backup != "" {
+		return os.RemoveAll(backup)
+	}
+	return nil
+}
+
+// CompareVersions compares two pack versions, ignoring a leading "v". As in
+// semver, a "-" suffix marks a pre-release, which is older than the release
+// it precedes: 1.2.0-rc1 < 1.2.0. Release and pre-release parts compare dot
+// segment by segment, numeric segments as numbers and others as strings, and
+// a part that runs out of segments first is older. It returns -1, 0 or 1.
+func CompareVersions(a, b string) int {
+	aRelease, aPre, aHasPre := strings.Cut(strings.TrimPrefix(a, "v"), "-")
+	bRelease, bPre, bHasPre := strings.Cut(strings.TrimPrefix(b, "v"), "-")
+	if c := compareSegments(aRelease, bRelease); c != 0 {
+		return c
+	}
+	switch {
+	case aHasPre && !bHasPre:
+		return -1
+	case !aHasPre && bHasPre:
+		return 1
+	}
+	return compareSegments(aPre, bPre)
+}
+
+func compareSegments(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.ParseUint(as[i], 10, 64)
+		bn, bErr := strconv.ParseUint(bs[i], 10, 64)
+		if aErr == nil && bErr == nil {
+			if c := cmp.Compare(an, bn); c != 0 {
+				return c
+			}
+			continue
+		}
+		if c := strings.Compare(as[i], bs[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
+// InstalledVersion returns the version of the pack installed in dir, or ""
+// when there is none.
+func InstalledVersion(dir string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return "", fmt.Errorf("parsing %s: %w", filepath.Join(dir, ManifestFile), err)
+	}
+	return m.Version, nil
+}
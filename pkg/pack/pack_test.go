@@ -0,0 +1,190 @@
+package pack
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFiles = map[string][]byte{
+	"new-llm.yaml": []byte("name: new-llm\n"),
+	"other.yml":    []byte("name: other\n"),
+}
+
+func newKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	return pub, priv
+}
+
+func buildPack(t *testing.T, priv ed25519.PrivateKey) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, "2026.10.1", testFiles, priv))
+	return buf.Bytes()
+}
+
+// rewritePack rebuilds a pack tarball, letting edit change, add or drop entries.
+func rewritePack(t *testing.T, data []byte, edit func(entries map[string][]byte)) []byte {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	entries := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			break
+		}
+		var b bytes.Buffer
+		_, _ = b.ReadFrom(tr)
+		entries[hdr.Name] = b.Bytes()
+	}
+	edit(entries)
+
+	var out bytes.Buffer
+	gw := gzip.NewWriter(&out)
+	tw := tar.NewWriter(gw)
+	for name, content := range entries {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}))
+		_, err := tw.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return out.Bytes()
+}
+
+func TestReadVerifiesPack(t *testing.T) {
+	pub, priv := newKey(t)
+	p, err := Read(bytes.NewReader(buildPack(t, priv)), pub)
+	require.NoError(t, err)
+	assert.Equal(t, "2026.10.1", p.Manifest.Version)
+	assert.Equal(t, testFiles, p.Files)
+}
+
+func TestReadRejects(t *testing.T) {
+	pub, priv := newKey(t)
+	otherPub, _ := newKey(t)
+	good := buildPack(t, priv)
+
+	tests := []struct {
+		name string
+		data []byte
+		key  ed25519.PublicKey
+		want string
+	}{
+		{"wrong key", good, otherPub, ErrSignature.Error()},
+		{"tampered probe", rewritePack(t, good, func(e map[string][]byte) { e["new-llm.yaml"] = []byte("name: evil\n") }), pub, "does not match its manifest hash"},
+		{"unlisted probe", rewritePack(t, good, func(e map[string][]byte) { e["extra.yaml"] = []byte("name: extra\n") }), pub, "extra.yaml is not in the manifest"},
+		{"missing probe", rewritePack(t, good, func(e map[string][]byte) { delete(e, "other.yml") }), pub, "missing other.yml"},
+		{"unsigned", rewritePack(t, good, func(e map[string][]byte) { delete(e, SignatureFile) }), pub, "no manifest.sig"},
+		{"path traversal", rewritePack(t, good, func(e map[string][]byte) { e["../evil.yaml"] = nil }), pub, "not at the top level"},
+		{"nested file", rewritePack(t, good, func(e map[string][]byte) { e["sub/x.yaml"] = nil }), pub, "not at the top level"},
+		{"non-probe file", rewritePack(t, good, func(e map[string][]byte) { e["run.sh"] = nil }), pub, "not a probe file"},
+		{"not gzip", []byte("plain"), pub, "reading probe pack"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(bytes.NewReader(tt.data), tt.key)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	pub, priv := newKey(t)
+	data := buildPack(t, priv)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pack.tar.gz" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	p, err := Fetch(context.Background(), server.Client(), server.URL+"/pack.tar.gz", pub)
+	require.NoError(t, err)
+	assert.Len(t, p.Files, 2)
+
+	_, err = Fetch(context.Background(), server.Client(), server.URL+"/missing", pub)
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestInstall(t *testing.T) {
+	pub, priv := newKey(t)
+	p, err := Read(bytes.NewReader(buildPack(t, priv)), pub)
+	require.NoError(t, err)
+
+	dir := filepath.Join(t.TempDir(), "julius", "probes")
+	version, err := InstalledVersion(dir)
+	require.NoError(t, err)
+	assert.Empty(t, version)
+
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "precious.yaml"), []byte("name: precious\n"), 0o644))
+	assert.ErrorContains(t, p.Install(dir), "holds no probe pack", "a directory that is not a pack is never replaced")
+	assert.FileExists(t, filepath.Join(dir, "precious.yaml"))
+	require.NoError(t, os.Remove(filepath.Join(dir, "precious.yaml")))
+
+	require.NoError(t, p.Install(dir), "an empty directory is installed into")
+	version, err = InstalledVersion(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "2026.10.1", version)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stale.yaml"), []byte("name: stale\n"), 0o644))
+	require.NoError(t, p.Install(dir))
+
+	got, err := os.ReadFile(filepath.Join(dir, "new-llm.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, testFiles["new-llm.yaml"], got)
+	assert.NoFileExists(t, filepath.Join(dir, "stale.yaml"), "the previous pack is replaced, not merged")
+
+	entries, err := os.ReadDir(filepath.Dir(dir))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no staging directories left behind")
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"2026.10.1", "2026.10.1", 0},
+		{"2026.10.2", "2026.10.1", 1},
+		{"2026.9.1", "2026.10.1", -1},
+		{"2026.10", "2026.10.1", -1},
+		{"v2", "v10", -1},
+		{"2026.10.1-rc2", "2026.10.1-rc1", 1},
+		{"1.2.0", "1.2.0-rc1", 1},
+		{"1.2.0-rc1", "1.2.0", -1},
+		{"1.2.0-rc.2", "1.2.0-rc.10", -1},
+		{"1.2.1-rc1", "1.2.0", 1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CompareVersions(tt.a, tt.b), "%s vs %s", tt.a, tt.b)
+	}
+}
+
+func TestParsePublicKey(t *testing.T) {
+	pub, _ := newKey(t)
+	got, err := ParsePublicKey(base64.StdEncoding.EncodeToString(pub) + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, pub, got)
+
+	_, err = ParsePublicKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.ErrorContains(t, err, "public key is 5 bytes")
+}
@@ -0,0 +1,157 @@
+package probe
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// ResolveExtends returns probes with every extends: chain resolved against the
+// other probes in the set. Order is preserved.
+func ResolveExtends(probes []*types.Probe) ([]*types.Probe, error) {
+	byName := make(map[string]*types.Probe, len(probes))
+	for _, p := range probes {
+		byName[p.Name] = p
+	}
+
+	resolved := make([]*types.Probe, len(probes))
+	for i, p := range probes {
+		r, err := Resolve(p, byName)
+		if err != nil {
+			return nil, err
+		}
+		resolved[i] = r
+	}
+	return resolved, nil
+}
+
+// Resolve merges p over the probe it extends, looked up by name in byName,
+// recursively. A probe without extends is returned unchanged. Fields set on
+// the child win:
+//
+//   - name is never inherited; description, category, tags, port_hint,
+//     specificity, require and api_docs are inherited when unset.
+//   - requests and mcp are inherited whole when the child has none, and
+//     replaced whole when it does.
+//   - models and augustus are merged field by field; the headers, fields and
+//     extra maps are merged key by key, and pagination is replaced whole.
+func Resolve(p *types.Probe, byName map[string]*types.Probe) (*types.Probe, error) {
+	return resolve(p, byName, nil)
+}
+
+func resolve(p *types.Probe, byName map[string]*types.Probe, chain []string) (*types.Probe, error) {
+	if p.Extends == "" {
+		return p, nil
+	}
+	chain = append(chain, p.Name)
+	if slices.Contains(chain, p.Extends) {
+		return nil, fmt.Errorf("probe %s: extends cycle: %s -> %s", chain[0], strings.Join(chain, " -> "), p.Extends)
+	}
+	parent, ok := byName[p.Extends]
+	if !ok {
+		return nil, fmt.Errorf("probe %s: extends unknown probe %q", p.Name, p.Extends)
+	}
+	parent, err := resolve(parent, byName, chain)
+	if err != nil {
+		return nil, err
+	}
+	return mergeProbe(parent, p), nil
+}
+
+func mergeProbe(parent, child *types.Probe) *types.Probe {
+	merged := *child
+	merged.Description = cmp.Or(child.Description, parent.Description)
+	merged.Category = cmp.Or(child.Category, parent.Category)
+	merged.PortHint = cmp.Or(child.PortHint, parent.PortHint)
+	merged.Specificity = cmp.Or(child.Specificity, parent.Specificity)
+	merged.Require = cmp.Or(child.Require, parent.Require)
+	merged.APIDocs = cmp.Or(child.APIDocs, parent.APIDocs)
+	if len(child.Tags) == 0 {
+		merged.Tags = slices.Clone(parent.Tags)
+	}
+
+	if len(child.Requests) == 0 {
+		merged.Requests = slices.Clone(parent.Requests)
+	}
+	if child.MCP == nil && parent.MCP != nil {
+		mcp := *parent.MCP
+		mcp.DiscoveryPaths = slices.Clone(parent.MCP.DiscoveryPaths)
+		merged.MCP = &mcp
+	}
+
+	switch {
+	case child.Models == nil && parent.Models != nil:
+		m := *parent.Models
+		m.Headers = maps.Clone(parent.Models.Headers)
+		m.Fields = maps.Clone(parent.Models.Fields)
+		merged.Models = &m
+	case child.Models != nil && parent.Models != nil:
+		merged.Models = mergeModels(parent.Models, child.Models)
+	}
+
+	switch {
+	case child.Augustus == nil && parent.Augustus != nil:
+		aug := *parent.Augustus
+		aug.ConfigTemplate = mergeGeneratorConfig(parent.Augustus.ConfigTemplate, types.GeneratorConfig{})
+		merged.Augustus = &aug
+	case child.Augustus != nil && parent.Augustus != nil:
+		merged.Augustus = &types.AugustusConfig{
+			Generator:      cmp.Or(child.Augustus.Generator, parent.Augustus.Generator),
+			ConfigTemplate: mergeGeneratorConfig(parent.Augustus.ConfigTemplate, child.Augustus.ConfigTemplate),
+		}
+	}
+
+	return &merged
+}
+
+func mergeModels(parent, child *types.ModelsConfig) *types.ModelsConfig {
+	m := types.ModelsConfig{
+		Path:       cmp.Or(child.Path, parent.Path),
+		Method:     cmp.Or(child.Method, parent.Method),
+		Headers:    mergeMaps(parent.Headers, child.Headers),
+		Body:       cmp.Or(child.Body, parent.Body),
+		Extract:    cmp.Or(child.Extract, parent.Extract),
+		Items:      cmp.Or(child.Items, parent.Items),
+		Fields:     mergeMaps(parent.Fields, child.Fields),
+		Pagination: parent.Pagination,
+	}
+	if child.Pagination != nil {
+		m.Pagination = child.Pagination
+	}
+	return &m
+}
+
+func mergeGeneratorConfig(parent, child types.GeneratorConfig) types.GeneratorConfig {
+	return types.GeneratorConfig{
+		Type:         cmp.Or(child.Type, parent.Type),
+		Endpoint:     cmp.Or(child.Endpoint, parent.Endpoint),
+		APIKey:       cmp.Or(child.APIKey, parent.APIKey),
+		Model:        cmp.Or(child.Model, parent.Model),
+		Method:       cmp.Or(child.Method, parent.Method),
+		Headers:      mergeMaps(parent.Headers, child.Headers),
+		Body:         cmp.Or(child.Body, parent.Body),
+		ResponsePath: cmp.Or(child.ResponsePath, parent.ResponsePath),
+		ResponseType: cmp.Or(child.ResponseType, parent.ResponseType),
+		Proxy:        cmp.Or(child.Proxy, parent.Proxy),
+		Timeout:      cmp.Or(child.Timeout, parent.Timeout),
+		Extra:        mergeMaps(parent.Extra, child.Extra),
+	}
+}
+
+// mergeMaps returns parent's entries overlaid with child's, or nil if both
+// are empty.
+func mergeMaps[V any](parent, child map[string]V) map[string]V {
+	if len(parent) == 0 && len(child) == 0 {
+		return nil
+	}
+	merged := maps.Clone(parent)
+	if merged == nil {
+		merged = make(map[string]V, len(child))
+	}
+	maps.Copy(merged, child)
+	return merged
+}
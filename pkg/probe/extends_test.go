@@ -0,0 +1,117 @@
+package probe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/rules"
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+func TestResolveExtends(t *testing.T) {
+	base, err := ParseProbe([]byte(`
+name: base
+description: Base
+category: generic
+specificity: 1
+requests:
+  - path: /v1/models
+    match:
+      - type: status
+        value: 200
+models:
+  path: /v1/models
+  extract: ".data[].id"
+  fields:
+    name: ".id"
+    owned_by: ".owned_by"
+  pagination:
+    type: cursor
+    param: after
+augustus:
+  generator: openai
+  config_template:
+    endpoint: "$TARGET"
+    model: "$MODEL"
+    api_key: "$API_KEY"
+    timeout: 180
+`))
+	require.NoError(t, err)
+	child, err := ParseProbe([]byte(`
+name: child
+extends: base
+category: self-hosted
+specificity: 75
+models:
+  fields:
+    max_model_len: ".max_model_len"
+augustus:
+  config_template:
+    api_key: "not-needed"
+`))
+	require.NoError(t, err)
+
+	resolved, err := ResolveExtends([]*types.Probe{base, child})
+	require.NoError(t, err)
+	require.Len(t, resolved, 2)
+	assert.Same(t, base, resolved[0], "probes without extends are unchanged")
+
+	got := resolved[1]
+	assert.Equal(t, "child", got.Name)
+	assert.Equal(t, "Base", got.Description, "unset scalars are inherited")
+	assert.Equal(t, "self-hosted", got.Category)
+	assert.Equal(t, 75, got.Specificity)
+	assert.Equal(t, base.Requests, got.Requests, "requests inherited when the child has none")
+
+	require.NotNil(t, got.Models)
+	assert.Equal(t, "/v1/models", got.Models.Path)
+	assert.Equal(t, map[string]string{"name": ".id", "owned_by": ".owned_by", "max_model_len": ".max_model_len"}, got.Models.Fields)
+	assert.Equal(t, base.Models.Pagination, got.Models.Pagination)
+
+	require.NotNil(t, got.Augustus)
+	assert.Equal(t, "openai", got.Augustus.Generator)
+	assert.Equal(t, "not-needed", got.Augustus.ConfigTemplate.APIKey)
+	assert.Equal(t, 180, got.Augustus.ConfigTemplate.Timeout)
+
+	got.Models.Fields["extra"] = ".x"
+	assert.NotContains(t, base.Models.Fields, "extra", "the parent is not mutated")
+}
+
+func TestResolveExtends_ReplacesRequests(t *testing.T) {
+	match := []rules.RawRule{{Type: "status", Value: 200}}
+	base := &types.Probe{Name: "base", Requests: []types.Request{{Path: "/a", RawMatch: match}, {Path: "/b", RawMatch: match}}}
+	child := &types.Probe{Name: "child", Extends: "base", Requests: []types.Request{{Path: "/c", RawMatch: match}}}
+
+	resolved, err := ResolveExtends([]*types.Probe{child, base})
+	require.NoError(t, err)
+	require.Len(t, resolved[0].Requests, 1)
+	assert.Equal(t, "/c", resolved[0].Requests[0].Path)
+}
+
+func TestResolveExtends_Chain(t *testing.T) {
+	a := &types.Probe{Name: "a", PortHint: 8000}
+	b := &types.Probe{Name: "b", Extends: "a", Category: "gateway"}
+	c := &types.Probe{Name: "c", Extends: "b"}
+
+	resolved, err := ResolveExtends([]*types.Probe{a, b, c})
+	require.NoError(t, err)
+	assert.Equal(t, 8000, resolved[2].PortHint)
+	assert.Equal(t, "gateway", resolved[2].Category)
+}
+
+func TestResolveExtends_Errors(t *testing.T) {
+	_, err := ResolveExtends([]*types.Probe{{Name: "a", Extends: "missing"}})
+	assert.EqualError(t, err, `probe a: extends unknown probe "missing"`)
+
+	_, err = ResolveExtends([]*types.Probe{
+		{Name: "a", Extends: "b"},
+		{Name: "b", Extends: "c"},
+		{Name: "c", Extends: "a"},
+	})
+	assert.EqualError(t, err, "probe a: extends cycle: a -> b -> c -> a")
+
+	_, err = ResolveExtends([]*types.Probe{{Name: "self", Extends: "self"}})
+	assert.EqualError(t, err, "probe self: extends cycle: self -> self")
+}
@@ -0,0 +1,73 @@
+package probe
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// Filter selects probes by name, category, specificity and tags. Zero-value
+// fields do not restrict the selection.
+type Filter struct {
+	Names          []string // names or path.Match globs to keep
+	ExcludeNames   []string // names or globs to drop, applied after Names
+	Categories     []string
+	MinSpecificity int
+	Tags           []string // keep probes carrying any of these tags
+}
+
+// Validate reports malformed name globs.
+func (f Filter) Validate() error {
+	for _, pattern := range slices.Concat(f.Names, f.ExcludeNames) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid probe pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Apply returns the probes f selects, in their original order.
+func (f Filter) Apply(probes []*types.Probe) []*types.Probe {
+	var selected []*types.Probe
+	for _, p := range probes {
+		if f.Matches(p) {
+			selected = append(selected, p)
+		}
+	}
+	return selected
+}
+
+// Matches reports whether f selects p. Category and tag comparisons are
+// case-insensitive.
+func (f Filter) Matches(p *types.Probe) bool {
+	if len(f.Names) > 0 && !matchesAny(f.Names, p.Name) {
+		return false
+	}
+	if matchesAny(f.ExcludeNames, p.Name) {
+		return false
+	}
+	if len(f.Categories) > 0 && !slices.ContainsFunc(f.Categories, func(c string) bool {
+		return strings.EqualFold(c, p.Category)
+	}) {
+		return false
+	}
+	if p.GetSpecificity() < f.MinSpecificity {
+		return false
+	}
+	if len(f.Tags) > 0 && !slices.ContainsFunc(f.Tags, func(t string) bool {
+		return slices.ContainsFunc(p.Tags, func(pt string) bool { return strings.EqualFold(t, pt) })
+	}) {
+		return false
+	}
+	return true
+}
+
+func matchesAny(patterns []string, name string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	})
+}
@@ -0,0 +1,52 @@
+package probe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+func filterNames(probes []*types.Probe) []string {
+	names := make([]string, 0, len(probes))
+	for _, p := range probes {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestFilter_Apply(t *testing.T) {
+	probes := []*types.Probe{
+		{Name: "ollama", Category: "self-hosted", Specificity: 90},
+		{Name: "vllm", Category: "self-hosted", Specificity: 75, Tags: []string{"openai-api"}},
+		{Name: "litellm", Category: "gateway", Specificity: 80, Tags: []string{"openai-api"}},
+		{Name: "openai-compatible", Category: "generic", Specificity: 1, Tags: []string{"openai-api"}},
+		{Name: "mcp-server", Category: "mcp"},
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"zero filter keeps all", Filter{}, []string{"ollama", "vllm", "litellm", "openai-compatible", "mcp-server"}},
+		{"names and globs", Filter{Names: []string{"ollama", "*llm"}}, []string{"ollama", "vllm", "litellm"}},
+		{"exclude after include", Filter{Names: []string{"*llm"}, ExcludeNames: []string{"lite*"}}, []string{"vllm"}},
+		{"category", Filter{Categories: []string{"Self-Hosted", "mcp"}}, []string{"ollama", "vllm", "mcp-server"}},
+		{"min specificity uses default", Filter{MinSpecificity: 50}, []string{"ollama", "vllm", "litellm", "mcp-server"}},
+		{"tags", Filter{Tags: []string{"OPENAI-API"}}, []string{"vllm", "litellm", "openai-compatible"}},
+		{"combined", Filter{Categories: []string{"self-hosted"}, Tags: []string{"openai-api"}}, []string{"vllm"}},
+		{"no match", Filter{Names: []string{"nothing"}}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, filterNames(tt.filter.Apply(probes)))
+		})
+	}
+}
+
+func TestFilter_Validate(t *testing.T) {
+	assert.NoError(t, Filter{Names: []string{"vllm", "ollama*"}}.Validate())
+	assert.ErrorContains(t, Filter{ExcludeNames: []string{"[bad"}}.Validate(), `invalid probe pattern "[bad"`)
+}
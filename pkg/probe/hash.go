@@ -0,0 +1,29 @@
+package probe
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// HashProbes returns a sha256 over the parsed probe definitions, independent
+// of load order. Two scans with the same hash ran identical probe logic, even
+// if the YAML differed in comments or formatting.
+func HashProbes(probes []*types.Probe) string {
+	sorted := make([]*types.Probe, len(probes))
+	copy(sorted, probes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	h := sha256.New()
+	encoder := json.NewEncoder(h)
+	for _, p := range sorted {
+		// Probes are plain data; encoding cannot fail.
+		_ = encoder.Encode(p)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
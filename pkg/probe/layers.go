@@ -0,0 +1,73 @@
+package probe
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// Source is one layer of probe definitions: the probe files in Dir of FS.
+type Source struct {
+	Name   string // recorded in Probe.Source, e.g. "embedded" or a directory path
+	FS     fs.FS
+	Dir    string
+	Strict bool // reject unknown keys (see ParseProbeStrict)
+}
+
+// DirSource is the Source for the probe files in a directory on disk.
+func DirSource(dir string) Source {
+	return Source{Name: dir, FS: os.DirFS(dir), Dir: "."}
+}
+
+// LoadLayers loads sources in order. A probe whose name is already defined by
+// an earlier source replaces it in place; new names are appended. extends is
+// resolved across the merged set, so a probe in a later source can extend one
+// shipped in an earlier one.
+func LoadLayers(sources ...Source) ([]*types.Probe, error) {
+	var (
+		merged []*types.Probe
+		index  = make(map[string]int)
+	)
+	for _, src := range sources {
+		probes, err := parseProbesFromFS(src.FS, src.Dir, src.Strict)
+		if err != nil {
+			return nil, fmt.Errorf("loading probes from %s: %w", src.Name, err)
+		}
+		for _, p := range probes {
+			p.Source = src.Name
+			if i, ok := index[p.Name]; ok {
+				merged[i] = p
+				continue
+			}
+			index[p.Name] = len(merged)
+			merged = append(merged, p)
+		}
+	}
+	return ResolveExtends(merged)
+}
+
+// LoadFiles loads src without merging same-named probes, recording each
+// probe's file name in Probe.Source, for tools that inspect the files rather
+// than the effective set. extends resolves to the last probe of a name in src,
+// falling back to base, so files meant for --extra-probes can extend shipped
+// probes.
+func LoadFiles(src Source, base ...*types.Probe) ([]*types.Probe, error) {
+	probes, err := parseProbesFromFS(src.FS, src.Dir, src.Strict)
+	if err != nil {
+		return nil, fmt.Errorf("loading probes from %s: %w", src.Name, err)
+	}
+
+	byName := make(map[string]*types.Probe, len(base)+len(probes))
+	for _, p := range slices.Concat(base, probes) {
+		byName[p.Name] = p
+	}
+	for i, p := range probes {
+		if probes[i], err = Resolve(p, byName); err != nil {
+			return nil, err
+		}
+	}
+	return probes, nil
+}
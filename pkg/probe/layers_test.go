@@ -0,0 +1,56 @@
+package probe
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+func TestLoadLayers(t *testing.T) {
+	const request = "requests:\n  - path: /\n    match:\n      - type: status\n        value: 200\n"
+	base := fstest.MapFS{
+		"a.yaml": {Data: []byte("name: a\ndescription: shipped a\ncategory: generic\nport_hint: 8000\n" + request)},
+		"b.yaml": {Data: []byte("name: b\ndescription: shipped b\n" + request)},
+	}
+	custom := fstest.MapFS{
+		"b.yaml": {Data: []byte("name: b\ndescription: private b\n" + request)},
+		"c.yaml": {Data: []byte("name: c\nextends: a\ndescription: private c\n")},
+	}
+
+	loaded, err := LoadLayers(
+		Source{Name: "embedded", FS: base, Dir: "."},
+		Source{Name: "./private", FS: custom, Dir: "."},
+	)
+	require.NoError(t, err)
+	require.Len(t, loaded, 3)
+
+	assert.Equal(t, "a", loaded[0].Name)
+	assert.Equal(t, "embedded", loaded[0].Source)
+
+	assert.Equal(t, "b", loaded[1].Name, "an override keeps the overridden probe's position")
+	assert.Equal(t, "private b", loaded[1].Description)
+	assert.Equal(t, "./private", loaded[1].Source)
+
+	assert.Equal(t, "c", loaded[2].Name)
+	assert.Equal(t, "./private", loaded[2].Source)
+	assert.Equal(t, 8000, loaded[2].PortHint, "extends resolves across sources")
+	assert.Len(t, loaded[2].Requests, 1)
+}
+
+func TestLoadLayers_SourceError(t *testing.T) {
+	_, err := LoadLayers(DirSource("/nonexistent/path"))
+	assert.ErrorContains(t, err, "loading probes from /nonexistent/path")
+}
+
+func TestHashProbes_IgnoresSource(t *testing.T) {
+	a, err := ParseProbe([]byte("name: a\n"))
+	require.NoError(t, err)
+	b, err := ParseProbe([]byte("name: a\n"))
+	require.NoError(t, err)
+	b.Source = "./private"
+	assert.Equal(t, HashProbes([]*types.Probe{a}), HashProbes([]*types.Probe{b}))
+}
@@ -37,6 +37,21 @@ func LoadProbesFromFS(fsys embed.FS, dir string) ([]*types.Probe, error) {
 }
 
 func loadProbesFromFS(fsys fs.FS, dir string) ([]*types.Probe, error) {
+	probes, err := parseProbesFromFS(fsys, dir, false)
+	if err != nil {
+		return nil, err
+	}
+	return ResolveExtends(probes)
+}
+
+// parseProbesFromFS parses every probe file in dir without resolving extends.
+// strict rejects unknown keys (see ParseProbeStrict).
+func parseProbesFromFS(fsys fs.FS, dir string, strict bool) ([]*types.Probe, error) {
+	parse := ParseProbe
+	if strict {
+		parse = ParseProbeStrict
+	}
+
 	entries, err := fs.ReadDir(fsys, dir)
 	if err != nil {
 		return nil, fmt.Errorf("reading probe directory: %w", err)
@@ -57,11 +72,12 @@ func loadProbesFromFS(fsys fs.FS, dir string) ([]*types.Probe, error) {
 			return nil, fmt.Errorf("reading %s: %w", path, err)
 		}
 
-		p, err := ParseProbe(data)
+		p, err := parse(data)
 		if err != nil {
 			return nil, fmt.Errorf("parsing %s: %w", path, err)
 		}
 
+		p.Source = path
 		probes = append(probes, p)
 	}
 
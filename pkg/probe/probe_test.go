@@ -1,6 +1,7 @@
 package probe
 
 import (
+	"encoding/json"
 	"net/http"
 	"os"
 	"testing"
@@ -70,10 +71,41 @@ func TestEmbeddedMCPServerProbe_EmitsAugustusConfig(t *testing.T) {
 	gc := configs[0]
 	assert.Equal(t, "mcp", gc.Type, "Type is populated from augustus.generator")
 	assert.Equal(t, "http://host:9099/mcp", gc.Endpoint, "$TARGET resolved to the target")
-	assert.Equal(t, map[string]string{"transport": "auto", "mode": "list_tools"}, gc.Extra,
+	assert.Equal(t, map[string]any{"transport": "auto", "mode": "list_tools"}, gc.Extra,
 		"the transport/mode extras ship verbatim")
 }
 
+// TestParseProbe_TypedExtra checks that extra values keep their YAML types
+// through the probe parser and into JSON output.
+func TestParseProbe_TypedExtra(t *testing.T) {
+	p, err := ParseProbe([]byte(`
+name: typed
+requests:
+  - path: /
+    match:
+      - type: status
+        value: 200
+augustus:
+  generator: rest
+  config_template:
+    endpoint: "$TARGET"
+    extra:
+      verify_tls: false
+      max_retries: 3
+      stop: ["$TARGET", "</s>"]
+      options:
+        stream: true
+`))
+	require.NoError(t, err)
+	require.NoError(t, types.ValidateExtra(p.Augustus.Generator, p.Augustus.ConfigTemplate.Extra))
+
+	configs := p.BuildGeneratorConfigs("https://host", nil)
+	require.Len(t, configs, 1)
+	b, err := json.Marshal(configs[0].Extra)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"verify_tls":false,"max_retries":3,"stop":["https://host","</s>"],"options":{"stream":true}}`, string(b))
+}
+
 func TestSortProbesByPortHint(t *testing.T) {
 	probeList := []*types.Probe{
 		{Name: "generic", PortHint: 0},
@@ -134,3 +166,48 @@ func TestMatchRules_EmptyRules(t *testing.T) {
 	result := MatchRules(resp, nil, []rules.Rule{})
 	assert.True(t, result) // Empty rules should pass (nothing to check)
 }
+
+func TestHashProbes(t *testing.T) {
+	a := &types.Probe{Name: "a", Requests: []types.Request{{Path: "/a", Method: "GET"}}}
+	b := &types.Probe{Name: "b", Requests: []types.Request{{Path: "/b", Method: "GET"}}}
+
+	hash := HashProbes([]*types.Probe{a, b})
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, HashProbes([]*types.Probe{b, a}), "hash should not depend on load order")
+
+	changed := &types.Probe{Name: "b", Requests: []types.Request{{Path: "/b2", Method: "GET"}}}
+	assert.NotEqual(t, hash, HashProbes([]*types.Probe{a, changed}), "hash should change with probe logic")
+}
+
+func TestParseProbeStrict(t *testing.T) {
+	data := []byte(`
+name: typo
+specifity: 90
+requests:
+  - path: /api
+    confidence: high
+    match:
+      - type: status
+        value: 200
+        headr: Server
+models:
+  path: /v1/models
+`)
+	_, err := ParseProbe(data)
+	require.NoError(t, err, "lenient parsing drops unknown keys")
+
+	_, err = ParseProbeStrict(data)
+	require.Error(t, err)
+	assert.Equal(t, `line 3: unknown key "specifity" (did you mean "specificity"?)
+line 6: unknown key "requests[0].confidence"
+line 10: unknown key "requests[0].match[0].headr" (did you mean "header"?)`, err.Error())
+
+	p, err := ParseProbeStrict([]byte("name: ok\naugustus:\n  config_template:\n    extra:\n      anything: goes\n"))
+	require.NoError(t, err, "extra is free-form")
+	assert.Equal(t, "ok", p.Name)
+}
+
+func TestEmbeddedProbes_Strict(t *testing.T) {
+	_, err := LoadLayers(Source{Name: "embedded", FS: probes.EmbeddedProbes, Dir: ".", Strict: true})
+	assert.NoError(t, err, "shipped probes must not carry unknown keys")
+}
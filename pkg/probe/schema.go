@@ -0,0 +1,118 @@
+package probe
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/praetorian-inc/julius/pkg/rules"
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// schemaDefNames renames Go types whose names would read oddly in the schema.
+var schemaDefNames = map[string]string{
+	"RawRule": "MatchRule",
+}
+
+// schemaRequired lists the keys a definition cannot omit. Requests, models
+// and most scalars may all come from a parent via extends.
+var schemaRequired = map[string][]string{
+	"Probe":            {"name"},
+	"MatchRule":        {"type"},
+	"PaginationConfig": {"type"},
+}
+
+// schemaProperties adds enums and descriptions to generated properties, keyed
+// by "Definition.key".
+func schemaProperties() map[string]map[string]any {
+	return map[string]map[string]any{
+		"Probe.name":            {"description": "Unique identifier; should match the file name"},
+		"Probe.extends":         {"description": "Name of a probe to inherit unset fields from"},
+		"Probe.category":        {"description": "Service category, e.g. self-hosted, gateway, mcp, rag-orchestration, cloud-managed, generic"},
+		"Probe.tags":            {"description": "Free-form labels for --tags selection"},
+		"Probe.specificity":     {"minimum": 0, "maximum": 100, "description": "Match specificity (1-100, 0 means the default of 50)"},
+		"Probe.require":         {"enum": []string{types.RequireAny, types.RequireAll}},
+		"Request.type":          {"enum": []string{types.RequestTypeHTTP, types.RequestTypeSSE}},
+		"Request.auth":          {"enum": types.AuthPostures},
+		"Request.path":          {"description": "Path appended to the target; empty sends the request to the target URL as given"},
+		"MatchRule.type":        {"enum": rules.Types()},
+		"MatchRule.value":       {"type": []string{"string", "integer"}},
+		"PaginationConfig.type": {"enum": []string{types.PaginationCursor, types.PaginationPage, types.PaginationLink}},
+		"MCPConfig.transport":   {"enum": []string{types.MCPTransportStreamableHTTP, types.MCPTransportSSE}},
+	}
+}
+
+// JSONSchema returns a JSON Schema (draft 2020-12) for probe YAML files,
+// generated from the types.Probe YAML tags so it cannot drift from the
+// parser. Unknown keys are rejected, as ParseProbeStrict rejects them.
+func JSONSchema() map[string]any {
+	b := &schemaBuilder{defs: map[string]any{}, props: schemaProperties()}
+	root := b.schemaFor(reflect.TypeFor[types.Probe]())
+	return map[string]any{
+		"$schema":     "https://json-schema.org/draft/2020-12/schema",
+		"title":       "Julius probe",
+		"description": "A julius probe definition",
+		"$ref":        root["$ref"],
+		"$defs":       b.defs,
+	}
+}
+
+type schemaBuilder struct {
+	defs  map[string]any
+	props map[string]map[string]any
+}
+
+func (b *schemaBuilder) schemaFor(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": b.schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.schemaFor(t.Elem())}
+	case reflect.Struct:
+		return b.structRef(t)
+	default:
+		return map[string]any{}
+	}
+}
+
+func (b *schemaBuilder) structRef(t reflect.Type) map[string]any {
+	name := t.Name()
+	if renamed, ok := schemaDefNames[name]; ok {
+		name = renamed
+	}
+	ref := map[string]any{"$ref": "#/$defs/" + name}
+	if _, ok := b.defs[name]; ok {
+		return ref
+	}
+	b.defs[name] = nil // reserve the name so recursive types terminate
+
+	fields := yamlFields(t)
+	properties := make(map[string]any, len(fields))
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		prop := b.schemaFor(fields[key].Type)
+		maps.Copy(prop, b.props[name+"."+key])
+		properties[key] = prop
+	}
+	def := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if required := schemaRequired[name]; len(required) > 0 {
+		def["required"] = required
+	}
+	b.defs[name] = def
+	return ref
+}
@@ -0,0 +1,152 @@
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/rules"
+	"github.com/praetorian-inc/julius/probes"
+)
+
+// checkSchema validates v against the subset of JSON Schema that JSONSchema
+// emits: $ref, type, properties, additionalProperties, items, enum, required.
+func checkSchema(root, schema map[string]any, v any, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		def := root["$defs"].(map[string]any)[strings.TrimPrefix(ref, "#/$defs/")]
+		return checkSchema(root, def.(map[string]any), v, path)
+	}
+	var problems []string
+	if enum, ok := schema["enum"].([]any); ok && !slices.Contains(enum, v) {
+		problems = append(problems, fmt.Sprintf("%s: %v not in %v", path, v, enum))
+	}
+	if typ, ok := schema["type"]; ok {
+		types, ok := typ.([]any)
+		if !ok {
+			types = []any{typ}
+		}
+		if !slices.ContainsFunc(types, func(t any) bool { return jsonTypeOf(v) == t || (t == "number" && jsonTypeOf(v) == "integer") }) {
+			return append(problems, fmt.Sprintf("%s: %s is not %v", path, jsonTypeOf(v), typ))
+		}
+	}
+	switch val := v.(type) {
+	case map[string]any:
+		props, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]any)
+		for _, req := range required {
+			if _, ok := val[req.(string)]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing %s", path, req))
+			}
+		}
+		for key, child := range val {
+			if prop, ok := props[key]; ok {
+				problems = append(problems, checkSchema(root, prop.(map[string]any), child, path+"."+key)...)
+				continue
+			}
+			switch extra := schema["additionalProperties"].(type) {
+			case bool:
+				if !extra {
+					problems = append(problems, fmt.Sprintf("%s: unknown key %s", path, key))
+				}
+			case map[string]any:
+				problems = append(problems, checkSchema(root, extra, child, path+"."+key)...)
+			}
+		}
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range val {
+				problems = append(problems, checkSchema(root, items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+	return problems
+}
+
+func jsonTypeOf(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if val == float64(int64(val)) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// loadSchema round-trips the schema through JSON, as editors will read it.
+func loadSchema(t *testing.T) map[string]any {
+	t.Helper()
+	data, err := json.Marshal(JSONSchema())
+	require.NoError(t, err)
+	var schema map[string]any
+	require.NoError(t, json.Unmarshal(data, &schema))
+	return schema
+}
+
+func toJSONValue(t *testing.T, data []byte) any {
+	t.Helper()
+	var doc any
+	require.NoError(t, yaml.Unmarshal(data, &doc))
+	j, err := json.Marshal(doc)
+	require.NoError(t, err)
+	var v any
+	require.NoError(t, json.Unmarshal(j, &v))
+	return v
+}
+
+func TestJSONSchema_AcceptsEmbeddedProbes(t *testing.T) {
+	schema := loadSchema(t)
+
+	entries, err := fs.ReadDir(probes.EmbeddedProbes, ".")
+	require.NoError(t, err)
+	for _, entry := range entries {
+		if !isTemplateFileExt(entry.Name()) {
+			continue
+		}
+		data, err := fs.ReadFile(probes.EmbeddedProbes, entry.Name())
+		require.NoError(t, err)
+		assert.Empty(t, checkSchema(schema, schema, toJSONValue(t, data), entry.Name()))
+	}
+
+	problems := checkSchema(schema, schema, toJSONValue(t, []byte(`
+name: typo
+specifity: 3
+requests:
+  - type: grpc
+    match:
+      - type: body.regex
+        value: x
+`)), "typo")
+	assert.ElementsMatch(t, []string{
+		"typo: unknown key specifity",
+		"typo.requests[0].type: grpc not in [http sse]",
+		fmt.Sprintf("typo.requests[0].match[0].type: body.regex not in %v", rules.Types()),
+	}, problems)
+}
+
+func TestJSONSchema_CoversProbeFields(t *testing.T) {
+	defs := loadSchema(t)["$defs"].(map[string]any)
+	probeProps := defs["Probe"].(map[string]any)["properties"].(map[string]any)
+	for _, key := range []string{"name", "extends", "tags", "requests", "models", "augustus", "mcp"} {
+		assert.Contains(t, probeProps, key)
+	}
+	assert.NotContains(t, probeProps, "Source", "fields without a YAML key are not in the schema")
+	assert.Contains(t, defs, "MatchRule")
+}
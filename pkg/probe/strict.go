@@ -0,0 +1,172 @@
+package probe
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// ParseProbeStrict is ParseProbe, but rejects keys that no probe field reads,
+// which yaml.Unmarshal would silently drop (e.g. `specifity:` or `matchs:`).
+func ParseProbeStrict(data []byte) (*types.Probe, error) {
+	unknown, err := UnknownKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing probe YAML: %w", err)
+	}
+	if len(unknown) > 0 {
+		errs := make([]error, len(unknown))
+		for i, msg := range unknown {
+			errs[i] = errors.New(msg)
+		}
+		return nil, errors.Join(errs...)
+	}
+	return ParseProbe(data)
+}
+
+// UnknownKeys returns one message per mapping key in a probe document that
+// does not correspond to a field of types.Probe, with its location and the
+// closest known key when one is a likely typo.
+func UnknownKeys(data []byte) ([]string, error) {
+	file, err := parser.ParseBytes(data, 0)
+	if err != nil {
+		return nil, err
+	}
+	var unknown []string
+	for _, doc := range file.Docs {
+		if doc.Body != nil {
+			unknown = append(unknown, unknownKeys(doc.Body, reflect.TypeFor[types.Probe](), "")...)
+		}
+	}
+	return unknown, nil
+}
+
+type mapNode interface {
+	MapRange() *ast.MapNodeIter
+}
+
+func unknownKeys(node ast.Node, t reflect.Type, path string) []string {
+	node = unwrapNode(node)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var unknown []string
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := node.(mapNode)
+		if !ok {
+			return nil
+		}
+		fields := yamlFields(t)
+		for iter := m.MapRange(); iter.Next(); {
+			if iter.Key().IsMergeKey() {
+				continue
+			}
+			key := iter.Key().GetToken().Value
+			field, ok := fields[key]
+			if !ok {
+				msg := fmt.Sprintf("line %d: unknown key %q", iter.Key().GetToken().Position.Line, joinPath(path, key))
+				if suggestion := closestKey(key, fields); suggestion != "" {
+					msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+				}
+				unknown = append(unknown, msg)
+				continue
+			}
+			unknown = append(unknown, unknownKeys(iter.Value(), field.Type, joinPath(path, key))...)
+		}
+	case reflect.Slice:
+		seq, ok := node.(*ast.SequenceNode)
+		if !ok {
+			return nil
+		}
+		for i, v := range seq.Values {
+			unknown = append(unknown, unknownKeys(v, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case reflect.Map:
+		m, ok := node.(mapNode)
+		if !ok {
+			return nil
+		}
+		for iter := m.MapRange(); iter.Next(); {
+			unknown = append(unknown, unknownKeys(iter.Value(), t.Elem(), joinPath(path, iter.Key().GetToken().Value))...)
+		}
+	}
+	return unknown
+}
+
+// unwrapNode looks through anchors and tags to the node they annotate.
+func unwrapNode(node ast.Node) ast.Node {
+	for {
+		switch n := node.(type) {
+		case *ast.AnchorNode:
+			node = n.Value
+		case *ast.TagNode:
+			node = n.Value
+		default:
+			return node
+		}
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// yamlFields maps each YAML key a struct decodes to its field.
+func yamlFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = strings.ToLower(f.Name)
+		}
+		fields[name] = f
+	}
+	return fields
+}
+
+// closestKey returns the known key within edit distance 2 of key, if any.
+func closestKey(key string, fields map[string]reflect.StructField) string {
+	best, bestDist := "", 3
+	for name := range fields {
+		if d := editDistance(key, name); d < bestDist || (d == bestDist && name < best) {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -33,6 +33,9 @@ func NewHeaderContainsRule(raw *RawRule) (Rule, error) {
 	if err != nil {
 		return nil, fmt.Errorf("header.contains %w", err)
 	}
+	if raw.Header == "" {
+		return nil, fmt.Errorf("header.contains requires header")
+	}
 	return &HeaderContainsRule{
 		BaseRule: BaseRule{Type: raw.Type, Not: raw.Not},
 		Header:   raw.Header,
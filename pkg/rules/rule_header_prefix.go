@@ -33,6 +33,9 @@ func NewHeaderPrefixRule(raw *RawRule) (Rule, error) {
 	if err != nil {
 		return nil, fmt.Errorf("header.prefix %w", err)
 	}
+	if raw.Header == "" {
+		return nil, fmt.Errorf("header.prefix requires header")
+	}
 	return &HeaderPrefixRule{
 		BaseRule: BaseRule{Type: raw.Type, Not: raw.Not},
 		Header:   raw.Header,
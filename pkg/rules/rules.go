@@ -2,7 +2,9 @@ package rules
 
 import (
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 )
 
 type Rule interface {
@@ -40,6 +42,11 @@ func Register(typeName string, decoder Decoder) {
 	ruleDecoders[typeName] = decoder
 }
 
+// Types returns the registered rule type names, sorted.
+func Types() []string {
+	return slices.Sorted(maps.Keys(ruleDecoders))
+}
+
 func (r *RawRule) ToRule() (Rule, error) {
 	decoder, ok := ruleDecoders[r.Type]
 	if !ok {
@@ -182,6 +182,14 @@ func TestUnmarshalRule(t *testing.T) {
 	}
 }
 
+func TestHeaderRules_RequireHeader(t *testing.T) {
+	for _, typ := range []string{"header.contains", "header.prefix"} {
+		raw := RawRule{Type: typ, Value: "uvicorn"}
+		_, err := raw.ToRule()
+		assert.EqualError(t, err, typ+" requires header")
+	}
+}
+
 func TestContentTypeRule_Match(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/praetorian-inc/julius/pkg/diff"
+	"github.com/praetorian-inc/julius/pkg/output"
+	"github.com/praetorian-inc/julius/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old> <new>",
+	Short: "Compare two scan result files",
+	Long: `Compare two result files written with -o json or -o jsonl and report
+what changed per target: services that appeared or disappeared, models
+added or removed, and category or error changes.
+
+Examples:
+  julius diff last-week.jsonl this-week.jsonl
+  julius diff -o json last-week.json this-week.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	oldResults, err := readResultsFile(args[0])
+	if err != nil {
+		return err
+	}
+	newResults, err := readResultsFile(args[1])
+	if err != nil {
+		return err
+	}
+
+	changes := diff.Compare(oldResults, newResults)
+	if err := diff.Write(os.Stdout, outputFormat, changes); err != nil {
+		return fmt.Errorf("writing output: %w", err)
+	}
+	return nil
+}
+
+func readResultsFile(path string) ([]types.Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening results file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	results, err := output.ReadResults(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return results, nil
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
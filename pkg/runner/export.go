@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/praetorian-inc/julius/pkg/export"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat string
+	exportDir    string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export probe definitions to other scanners' formats",
+	Long: `Export the loaded probe definitions as templates for other scanners.
+
+Supported formats:
+  nuclei   One Nuclei HTTP template per probe
+
+Without --out-dir, templates are written to stdout as a multi-document YAML
+stream. With --out-dir, each probe is written to <dir>/julius-<name>.yaml.
+
+Examples:
+  julius export --format nuclei --out-dir ./nuclei-templates
+  julius export --format nuclei -p ./my-probes > julius.yaml`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNoBanner: "true"},
+	RunE:        runExport,
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportFormat != "nuclei" {
+		return fmt.Errorf("unknown export format: %s", exportFormat)
+	}
+
+	loadedProbes, err := loadProbes()
+	if err != nil {
+		return fmt.Errorf("loading probes: %w", err)
+	}
+
+	if exportDir != "" {
+		if err := os.MkdirAll(exportDir, 0o755); err != nil {
+			return fmt.Errorf("creating output directory: %w", err)
+		}
+	}
+
+	exported := 0
+	for _, p := range loadedProbes {
+		tmpl, err := export.ToNucleiTemplate(p)
+		if errors.Is(err, export.ErrUnsupportedProbe) {
+			if !quiet {
+				fmt.Fprintf(os.Stderr, "Skipping %s: %v\n", p.Name, err)
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("exporting %s: %w", p.Name, err)
+		}
+		data, err := export.MarshalNuclei(tmpl)
+		if err != nil {
+			return err
+		}
+
+		if exportDir != "" {
+			path := filepath.Join(exportDir, tmpl.ID+".yaml")
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				return fmt.Errorf("writing %s: %w", path, err)
+			}
+			exported++
+			continue
+		}
+
+		if exported > 0 {
+			fmt.Println("---")
+		}
+		fmt.Print(string(data))
+		exported++
+	}
+
+	if exportDir != "" && !quiet {
+		fmt.Fprintf(os.Stderr, "Exported %d templates to %s\n", exported, exportDir)
+	}
+
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVar(&exportFormat, "format", "nuclei", "Export format (nuclei)")
+	exportCmd.Flags().StringVar(&exportDir, "out-dir", "", "Write one file per probe into this directory instead of stdout")
+}
@@ -0,0 +1,48 @@
+package runner
+
+import (
+	"github.com/praetorian-inc/julius/pkg/probe"
+	"github.com/praetorian-inc/julius/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	filterProbes     []string
+	filterExclude    []string
+	filterCategories []string
+	filterMinSpec    int
+	filterTags       []string
+)
+
+// addProbeFilterFlags registers the probe selection flags shared by probe and
+// list.
+func addProbeFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&filterProbes, "probes", nil, "Only use these probes (comma-separated names or globs, e.g. vllm,ollama*)")
+	cmd.Flags().StringSliceVar(&filterExclude, "exclude-probes", nil, "Skip these probes (comma-separated names or globs)")
+	cmd.Flags().StringSliceVar(&filterCategories, "category", nil, "Only use probes in these categories (e.g. self-hosted,gateway)")
+	cmd.Flags().IntVar(&filterMinSpec, "min-specificity", 0, "Only use probes with at least this specificity (1-100)")
+	cmd.Flags().StringSliceVar(&filterTags, "tags", nil, "Only use probes carrying any of these tags")
+}
+
+func probeFilter() probe.Filter {
+	return probe.Filter{
+		Names:          filterProbes,
+		ExcludeNames:   filterExclude,
+		Categories:     filterCategories,
+		MinSpecificity: filterMinSpec,
+		Tags:           filterTags,
+	}
+}
+
+// loadSelectedProbes loads probe definitions and applies the filter flags.
+func loadSelectedProbes() ([]*types.Probe, error) {
+	f := probeFilter()
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	loaded, err := loadProbes()
+	if err != nil {
+		return nil, err
+	}
+	return f.Apply(loaded), nil
+}
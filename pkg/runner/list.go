@@ -3,6 +3,7 @@ package runner
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
@@ -12,12 +13,17 @@ var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all available probe definitions",
 	Long: `List all probe definitions that are available for fingerprinting.
-Shows the name, description, port hint, and number of requests for each definition.`,
+Shows the name, description, port hint, and number of requests for each definition,
+and the source it was loaded from ("embedded" or a --probes-dir/--extra-probes directory).
+The probe selection flags show exactly the set a probe run with the same flags would use.
+
+Example:
+  julius list --category self-hosted --min-specificity 75`,
 	RunE: runList,
 }
 
 func runList(cmd *cobra.Command, args []string) error {
-	loadedProbes, err := loadProbes()
+	loadedProbes, err := loadSelectedProbes()
 	if err != nil {
 		return fmt.Errorf("loading probes: %w", err)
 	}
@@ -28,7 +34,7 @@ func runList(cmd *cobra.Command, args []string) error {
 	}
 
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"NAME", "DESCRIPTION", "PORT HINT", "REQUESTS", "SPECIFICITY", "CATEGORY"})
+	table.SetHeader([]string{"NAME", "DESCRIPTION", "PORT HINT", "REQUESTS", "SPECIFICITY", "CATEGORY", "TAGS", "SOURCE"})
 	table.SetBorder(false)
 	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
@@ -48,6 +54,8 @@ func runList(cmd *cobra.Command, args []string) error {
 			requestCount,
 			specificity,
 			p.Category,
+			strings.Join(p.Tags, ","),
+			p.Source,
 		})
 	}
 
@@ -58,4 +66,5 @@ func runList(cmd *cobra.Command, args []string) error {
 
 func init() {
 	rootCmd.AddCommand(listCmd)
+	addProbeFilterFlags(listCmd)
 }
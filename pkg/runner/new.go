@@ -0,0 +1,156 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/praetorian-inc/julius/pkg/scaffold"
+	"github.com/praetorian-inc/julius/pkg/scanner"
+	"github.com/praetorian-inc/julius/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	newFrom        string
+	newName        string
+	newPaths       []string
+	newOut         string
+	newMaxRequests int
+)
+
+var newCmd = &cobra.Command{
+	Use:   "new --from <target>",
+	Short: "Draft a probe from a live instance's responses",
+	Long: `Fetch common LLM paths from a known instance of a service and draft a probe
+YAML from what sets its responses apart. Each response is compared with the
+target's answer for a path it does not serve and with headers and JSON keys any
+web API sends. Distinctive headers, JSON keys, page titles and text banners are
+proposed as candidate match rules on the paths that returned them.
+
+The draft is a starting point, not a probe: other services may share a marker,
+so keep the rules only this service returns, then check the result with
+julius validate, julius lint and julius record. If an existing generic probe
+already matches the target, the draft extends it to inherit its models and
+augustus sections.
+
+Examples:
+  julius new --from http://localhost:8000 --name my-llm
+  julius new --from http://localhost:8000 --name my-llm --path /api/info --out probes/my-llm.yaml`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNoBanner: "true"},
+	RunE:        runNew,
+}
+
+func runNew(cmd *cobra.Command, args []string) error {
+	target := scanner.NormalizeTarget(newFrom)
+
+	if newOut != "" {
+		if _, err := os.Stat(newOut); err == nil {
+			return fmt.Errorf("%s already exists", newOut)
+		}
+	}
+
+	loaded, err := loadProbes()
+	if err != nil {
+		return fmt.Errorf("loading probes: %w", err)
+	}
+	tlsConfig, err := buildTLSConfig()
+	if err != nil {
+		return fmt.Errorf("building TLS config: %w", err)
+	}
+	headers, err := parseHeaders(customHeaders)
+	if err != nil {
+		return fmt.Errorf("parsing headers: %w", err)
+	}
+	s := scanner.NewScanner(
+		scanner.WithTimeout(time.Duration(timeout)*time.Second),
+		scanner.WithConcurrency(concurrency),
+		scanner.WithMaxResponseSize(maxResponseSize),
+		scanner.WithTLSConfig(tlsConfig),
+		scanner.WithHeaders(headers),
+	)
+
+	baseline, err := fetchPath(s, target, scaffold.BaselinePath)
+	if err != nil {
+		return fmt.Errorf("fetching baseline from %s: %w", target, err)
+	}
+
+	paths := slices.Clone(scaffold.CommonPaths)
+	for _, p := range newPaths {
+		if !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+	observed := make([]*scanner.Exchange, len(paths))
+	g := new(errgroup.Group)
+	g.SetLimit(concurrency)
+	for i, path := range paths {
+		g.Go(func() error {
+			// A path that fails is one fewer candidate, not a failed draft.
+			if ex, err := fetchPath(s, target, path); err == nil {
+				observed[i] = &ex
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	var exchanges []scanner.Exchange
+	for _, ex := range observed {
+		if ex != nil {
+			exchanges = append(exchanges, *ex)
+		}
+	}
+
+	draft := scaffold.Draft{
+		Name:       newName,
+		Target:     target,
+		PortHint:   scanner.ExtractPort(target),
+		Candidates: scaffold.Analyze(baseline, exchanges),
+		MaxRequest: newMaxRequests,
+	}
+	for _, r := range s.Scan(target, loaded, false) {
+		draft.Matched = append(draft.Matched, r.Service)
+		if draft.Extends == "" && r.Category == types.CategoryGeneric {
+			draft.Extends = r.Service
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetched %d of %d paths from %s: %d candidate requests\n", len(exchanges), len(paths), target, len(draft.Candidates))
+
+	out := os.Stdout
+	if newOut != "" {
+		f, err := os.OpenFile(newOut, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	return scaffold.Write(out, draft)
+}
+
+// fetchPath sends a GET for path through the scanner, so its response is
+// cached for the scan that follows.
+func fetchPath(s *scanner.Scanner, target, path string) (scanner.Exchange, error) {
+	exchanges, err := s.Record(target, &types.Probe{Requests: []types.Request{{Path: path}}})
+	if err != nil {
+		return scanner.Exchange{}, err
+	}
+	return exchanges[0], nil
+}
+
+func init() {
+	rootCmd.AddCommand(newCmd)
+	newCmd.Flags().StringVar(&newFrom, "from", "", "Target running the service to draft a probe for (required)")
+	newCmd.Flags().StringVar(&newName, "name", "new-service", "Name of the drafted probe")
+	newCmd.Flags().StringArrayVar(&newPaths, "path", nil, "Additional path to fetch. Can be specified multiple times")
+	newCmd.Flags().StringVar(&newOut, "out", "", "File to write the draft to (default: stdout)")
+	newCmd.Flags().IntVar(&newMaxRequests, "max-requests", 3, "Maximum number of requests in the draft; further candidates are listed in a comment")
+	newCmd.Flags().StringArrayVarP(&customHeaders, "header", "H", nil, "Custom HTTP header sent to the target (e.g., \"Authorization: Bearer token\"). Can be specified multiple times")
+	_ = newCmd.MarkFlagRequired("from")
+}
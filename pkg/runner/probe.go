@@ -4,9 +4,12 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/praetorian-inc/julius/pkg/credentials"
 	"github.com/praetorian-inc/julius/pkg/output"
 	"github.com/praetorian-inc/julius/pkg/probe"
 	"github.com/praetorian-inc/julius/pkg/scanner"
@@ -19,6 +22,18 @@ var (
 	augustusFlag  bool
 	basePaths     string
 	customHeaders []string
+	outputFiles   []string
+	envelopeFlag  bool
+	credsFile     string
+	showSecrets   bool
+	augustusOut   string
+	augustusFmt   string
+	garakOut      string
+	promptfooOut  string
+	verifyInfer   bool
+	mcpEnumerate  bool
+	mcpDiscover   bool
+	mcpOAuthExt   bool
 )
 
 var probeCmd = &cobra.Command{
@@ -35,7 +50,9 @@ Examples:
   julius probe https://api.example.com
   julius probe -f targets.txt
   cat targets.txt | julius probe -
-  julius probe https://api1.example.com https://api2.example.com`,
+  julius probe https://api1.example.com https://api2.example.com
+  julius probe --category self-hosted https://api.example.com
+  julius probe -f targets.txt -O jsonl:results.jsonl -O html:report.html`,
 	RunE: runProbe,
 }
 
@@ -52,13 +69,42 @@ func runProbe(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no targets specified. Use --help for usage information")
 	}
 
-	loadedProbes, err := loadProbes()
+	// Writing Augustus configs to files needs them in the results.
+	if augustusOut != "" {
+		if augustusFmt != "yaml" && augustusFmt != "json" {
+			return fmt.Errorf("unknown --augustus-format: %s", augustusFmt)
+		}
+		augustusFlag = true
+	}
+	if garakOut != "" && augustusOut != "" && filepath.Clean(garakOut) == filepath.Clean(augustusOut) {
+		return fmt.Errorf("--garak-out and --augustus-out must be different directories")
+	}
+	// garak and promptfoo configs are converted from the generator configs,
+	// which only --augustus puts in the written results.
+	buildConfigs := augustusFlag || garakOut != "" || promptfooOut != ""
+
+	// Parse file outputs before scanning so a typo doesn't cost a whole scan.
+	fileOutputs, err := parseOutputFiles(outputFiles)
+	if err != nil {
+		return fmt.Errorf("parsing output files: %w", err)
+	}
+	if envelopeFlag && !hasJSONOutput(fileOutputs) {
+		return fmt.Errorf("--envelope only applies to json output: use -o json or -O json:<file>")
+	}
+
+	loadedProbes, err := loadSelectedProbes()
 	if err != nil {
 		return fmt.Errorf("loading probes: %w", err)
 	}
 
 	if len(loadedProbes) == 0 {
-		return fmt.Errorf("no probe definitions found")
+		return fmt.Errorf("no probe definitions found (check --probes, --exclude-probes, --category, --min-specificity and --tags)")
+	}
+
+	probeSetHash := probe.HashProbes(loadedProbes)
+	probeNames := make([]string, 0, len(loadedProbes))
+	for _, p := range loadedProbes {
+		probeNames = append(probeNames, p.Name)
 	}
 
 	loadedProbes = expandWithBasePaths(loadedProbes)
@@ -73,22 +119,45 @@ func runProbe(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("parsing headers: %w", err)
 	}
 
+	var credStore *credentials.Store
+	if credsFile != "" {
+		credStore, err = credentials.Load(credsFile)
+		if err != nil {
+			return fmt.Errorf("loading credentials: %w", err)
+		}
+	}
+
 	timeoutDuration := time.Duration(timeout) * time.Second
-	s := scanner.NewScanner(
+	scanOpts := []scanner.Option{
 		scanner.WithTimeout(timeoutDuration),
 		scanner.WithConcurrency(concurrency),
 		scanner.WithMaxResponseSize(maxResponseSize),
 		scanner.WithTLSConfig(tlsConfig),
 		scanner.WithHeaders(headers),
-	)
+		scanner.WithCredentials(credStore),
+	}
+	if verifyInfer {
+		scanOpts = append(scanOpts, scanner.WithInferenceVerification())
+	}
+	if mcpEnumerate {
+		scanOpts = append(scanOpts, scanner.WithMCPEnumeration())
+	}
+	if mcpDiscover {
+		scanOpts = append(scanOpts, scanner.WithMCPDiscovery())
+	}
+	if mcpOAuthExt {
+		scanOpts = append(scanOpts, scanner.WithOAuthExternal())
+	}
+	s := scanner.NewScanner(scanOpts...)
 
 	var allResults []types.Result
+	startedAt := time.Now().UTC()
 
 	for _, target := range targets {
 		targetPort := scanner.ExtractPort(target)
 		sortedProbes := probe.SortProbesByPortHint(loadedProbes, targetPort)
 
-		results := s.Scan(target, sortedProbes, augustusFlag)
+		results := s.Scan(target, sortedProbes, buildConfigs)
 		if len(results) > 0 {
 			allResults = append(allResults, results...)
 		} else if !quiet {
@@ -96,18 +165,82 @@ func runProbe(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	writer, err := output.NewWriter(outputFormat, os.Stdout)
+	if !showSecrets {
+		credStore.RedactResults(allResults)
+	}
+
+	written := allResults
+	if !augustusFlag {
+		written = withoutGeneratorConfigs(allResults)
+	}
+
+	var writerOpts []output.WriterOption
+	if envelopeFlag {
+		writerOpts = append(writerOpts, output.WithMetadata(&types.ScanMetadata{
+			StartedAt:    startedAt,
+			FinishedAt:   time.Now().UTC(),
+			Version:      version(),
+			ProbeSetHash: probeSetHash,
+			Probes:       probeNames,
+			TargetCount:  len(targets),
+			Options:      scanOptions(headers),
+		}))
+	}
+
+	writer, err := output.NewWriter(outputFormat, os.Stdout, writerOpts...)
 	if err != nil {
 		return fmt.Errorf("creating output writer: %w", err)
 	}
 
-	if err := writer.Write(allResults); err != nil {
+	if err := writer.Write(written); err != nil {
 		return fmt.Errorf("writing output: %w", err)
 	}
 
+	for _, fo := range fileOutputs {
+		if err := fo.WriteFile(written, writerOpts...); err != nil {
+			return fmt.Errorf("writing output file: %w", err)
+		}
+	}
+
+	if augustusOut != "" {
+		if err := output.WriteGeneratorConfigs(augustusOut, augustusFmt, allResults); err != nil {
+			return fmt.Errorf("writing Augustus configs: %w", err)
+		}
+	}
+
+	if garakOut != "" {
+		if err := output.WriteGarakConfigs(garakOut, allResults); err != nil {
+			return fmt.Errorf("writing garak configs: %w", err)
+		}
+	}
+
+	if promptfooOut != "" {
+		if err := output.WritePromptfooProviders(promptfooOut, allResults); err != nil {
+			return fmt.Errorf("writing promptfoo providers: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// hasJSONOutput reports whether stdout or any file output is json, the only
+// format --envelope wraps.
+func hasJSONOutput(fileOutputs []output.FileOutput) bool {
+	return outputFormat == "json" || slices.ContainsFunc(fileOutputs, func(fo output.FileOutput) bool {
+		return fo.Format == "json"
+	})
+}
+
+// withoutGeneratorConfigs returns a copy of results with their generator
+// configs dropped, for output when they were only built for config files.
+func withoutGeneratorConfigs(results []types.Result) []types.Result {
+	stripped := slices.Clone(results)
+	for i := range stripped {
+		stripped[i].GeneratorConfigs = nil
+	}
+	return stripped
+}
+
 func loadTargets(args []string) ([]string, error) {
 	if len(args) == 0 {
 		stat, err := os.Stdin.Stat()
@@ -178,25 +311,108 @@ func parseHeaders(raw []string) (map[string]string, error) {
 	return headers, nil
 }
 
+// parseOutputFiles parses the "format:path" values of the --output-file flag.
+func parseOutputFiles(raw []string) ([]output.FileOutput, error) {
+	fileOutputs := make([]output.FileOutput, 0, len(raw))
+	for _, spec := range raw {
+		fo, err := output.ParseFileOutput(spec)
+		if err != nil {
+			return nil, err
+		}
+		fileOutputs = append(fileOutputs, fo)
+	}
+	return fileOutputs, nil
+}
+
+// scanOptions records the effective CLI options for the scan metadata envelope.
+func scanOptions(headers map[string]string) types.ScanOptions {
+	return types.ScanOptions{
+		ProbesDir:        probesDir,
+		ExtraProbes:      extraProbes,
+		BasePaths:        splitBasePaths(basePaths),
+		Headers:          redactHeaders(headers),
+		Timeout:          timeout,
+		Concurrency:      concurrency,
+		MaxResponseSize:  maxResponseSize,
+		Insecure:         insecureSkipVerify,
+		CACert:           caCertFile,
+		Augustus:         augustusFlag,
+		Credentials:      credsFile,
+		VerifyInference:  verifyInfer,
+		MCPEnumerate:     mcpEnumerate,
+		MCPDiscover:      mcpDiscover,
+		MCPOAuthExternal: mcpOAuthExt,
+		Probes:           filterProbes,
+		ExcludeProbes:    filterExclude,
+		Categories:       filterCategories,
+		MinSpecificity:   filterMinSpec,
+		Tags:             filterTags,
+	}
+}
+
+// sensitiveHeaderMarkers flag header names whose values are credentials.
+var sensitiveHeaderMarkers = []string{"auth", "key", "token", "secret", "cookie", "session", "password"}
+
+// redactHeaders returns a copy of headers with credential-bearing values
+// replaced, so scan metadata can be shared without leaking the keys used.
+func redactHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	redacted := make(map[string]string, len(headers))
+	for k, v := range headers {
+		lower := strings.ToLower(k)
+		for _, marker := range sensitiveHeaderMarkers {
+			if strings.Contains(lower, marker) {
+				v = "[REDACTED]"
+				break
+			}
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
 // expandWithBasePaths expands probes with base path prefixes from the --base-paths flag.
 func expandWithBasePaths(probes []*types.Probe) []*types.Probe {
 	if basePaths == "" {
 		return probes
 	}
-	raw := strings.Split(basePaths, ",")
-	parts := make([]string, 0, len(raw))
-	for _, p := range raw {
+	return probe.ExpandWithBasePaths(probes, splitBasePaths(basePaths))
+}
+
+// splitBasePaths splits the comma-separated --base-paths value, dropping blanks.
+func splitBasePaths(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	split := strings.Split(raw, ",")
+	parts := make([]string, 0, len(split))
+	for _, p := range split {
 		if t := strings.TrimSpace(p); t != "" {
 			parts = append(parts, t)
 		}
 	}
-	return probe.ExpandWithBasePaths(probes, parts)
+	return parts
 }
 
 func init() {
 	rootCmd.AddCommand(probeCmd)
 	probeCmd.Flags().StringVarP(&targetsFile, "file", "f", "", "Read targets from file")
 	probeCmd.Flags().BoolVar(&augustusFlag, "augustus", false, "Include Augustus generator configs in output")
+	probeCmd.Flags().StringVar(&augustusOut, "augustus-out", "", "Write each Augustus generator config to its own file in this directory, plus an index.json manifest (implies --augustus)")
+	probeCmd.Flags().StringVar(&augustusFmt, "augustus-format", "yaml", "File format for --augustus-out (yaml, json)")
+	probeCmd.Flags().BoolVar(&verifyInfer, "verify-inference", false, "After a match, send one minimal unauthenticated completion request to prove the endpoint serves inference")
+	probeCmd.Flags().BoolVar(&mcpEnumerate, "mcp-enumerate", false, "Complete an MCP session on open MCP servers and record their tools, resources and prompts; record the OAuth metadata of auth-gated ones")
+	probeCmd.Flags().BoolVar(&mcpDiscover, "mcp-discover", false, "Also look for MCP servers at common endpoint paths and the path advertised in /.well-known/oauth-protected-resource")
+	probeCmd.Flags().BoolVar(&mcpOAuthExt, "mcp-oauth-external", false, "Let --mcp-enumerate's OAuth discovery fetch metadata and authorization servers on hosts other than the target")
+	probeCmd.Flags().StringVar(&garakOut, "garak-out", "", "Write a garak generator option file per config to this directory, plus a garak-index.json manifest")
+	probeCmd.Flags().StringVar(&promptfooOut, "promptfoo-out", "", "Write a promptfoo providers list to this YAML file")
+	addProbeFilterFlags(probeCmd)
 	probeCmd.Flags().StringVar(&basePaths, "base-paths", "", "Comma-separated path prefixes to prepend to probe paths (e.g., /api,/proxy)")
 	probeCmd.Flags().StringArrayVarP(&customHeaders, "header", "H", nil, "Custom HTTP header (e.g., \"Authorization: Bearer token\"). Can be specified multiple times")
+	probeCmd.Flags().StringVar(&credsFile, "credentials", "", "YAML file of per-service/per-host API keys for authenticated model enumeration and $API_KEY in Augustus configs")
+	probeCmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Write credentials from --credentials into output instead of redacting them")
+	probeCmd.Flags().BoolVar(&envelopeFlag, "envelope", false, "Wrap json output (-o json and -O json: files) in an object with scan metadata (times, version, probe set hash, options); other formats are not wrapped")
+	probeCmd.Flags().StringArrayVarP(&outputFiles, "output-file", "O", nil, "Also write results to a file as format:path (e.g., jsonl:results.jsonl, html:report.html). Can be specified multiple times")
 }
@@ -0,0 +1,225 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/praetorian-inc/julius/pkg/fixture"
+	"github.com/praetorian-inc/julius/pkg/scanner"
+	"github.com/praetorian-inc/julius/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	recordProbe   string
+	recordCase    string
+	recordOut     string
+	recordAppend  bool
+	recordMaxBody int
+)
+
+var recordCmd = &cobra.Command{
+	Use:   "record <target> --probe <name>",
+	Short: "Record a fixture case from a live target",
+	Long: `Send a probe's requests and its models request to a live target and write
+the responses as a fixture case for julius test. The case expects whatever the
+probe did against the target: record a positive case from the service itself
+and negative cases from services the probe must not match.
+
+Only Content-Type and the headers the probe's rules inspect are recorded.
+Bodies over --max-body bytes are trimmed (JSON arrays keep their first items).
+API keys, bearer and basic credentials, JWTs and the values of credential JSON
+keys (api_key, access_token, password, ...) are replaced with REDACTED. The case is replayed before it is written; if trimmed bodies change
+the result, the bodies are recorded whole.
+
+The fixture is printed to stdout unless --out is given. With --append, the case
+is added to the existing fixture file at --out.
+
+Examples:
+  julius record http://localhost:8000 --probe vllm
+  julius record http://localhost:8000 --probe vllm --out fixtures/vllm.yaml
+  julius record http://localhost:11434 --probe vllm --case negative-ollama --out fixtures/vllm.yaml --append`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: map[string]string{annotationNoBanner: "true"},
+	RunE:        runRecord,
+}
+
+func runRecord(cmd *cobra.Command, args []string) error {
+	target := scanner.NormalizeTarget(args[0])
+
+	loaded, err := loadProbes()
+	if err != nil {
+		return fmt.Errorf("loading probes: %w", err)
+	}
+	i := slices.IndexFunc(loaded, func(p *types.Probe) bool { return p.Name == recordProbe })
+	if i < 0 {
+		return fmt.Errorf("unknown probe %q", recordProbe)
+	}
+	p := loaded[i]
+
+	var existing *fixture.Fixture
+	if recordOut != "" {
+		fx, err := fixture.ReadFile(recordOut)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			if recordAppend {
+				return fmt.Errorf("--append: %s does not exist", recordOut)
+			}
+		case err != nil:
+			return err
+		case !recordAppend:
+			return fmt.Errorf("%s already exists; pass --append to add the case to it", recordOut)
+		case fx.Probe != p.Name:
+			return fmt.Errorf("%s is a fixture for probe %s, not %s", recordOut, fx.Probe, p.Name)
+		default:
+			existing = &fx
+		}
+	} else if recordAppend {
+		return fmt.Errorf("--append requires --out")
+	}
+
+	tlsConfig, err := buildTLSConfig()
+	if err != nil {
+		return fmt.Errorf("building TLS config: %w", err)
+	}
+	headers, err := parseHeaders(customHeaders)
+	if err != nil {
+		return fmt.Errorf("parsing headers: %w", err)
+	}
+	s := scanner.NewScanner(
+		scanner.WithTimeout(time.Duration(timeout)*time.Second),
+		scanner.WithMaxResponseSize(maxResponseSize),
+		scanner.WithTLSConfig(tlsConfig),
+		scanner.WithHeaders(headers),
+	)
+
+	// Scanning first fills the cache, so recording replays the same responses.
+	results := s.Scan(target, []*types.Probe{p}, false)
+	exchanges, err := s.Record(target, p)
+	if err != nil {
+		return fmt.Errorf("recording %s: %w", target, err)
+	}
+
+	c := recordedCase(p, results, exchanges, recordMaxBody)
+	if existing != nil && slices.ContainsFunc(existing.Cases, func(ec fixture.Case) bool { return ec.Name == c.Name }) {
+		return fmt.Errorf("%s already has a case named %q; choose another with --case", recordOut, c.Name)
+	}
+
+	replay := func(c fixture.Case) error {
+		return c.Run(func(target string) []types.Result {
+			return scanner.NewScanner(scanner.WithTimeout(5*time.Second)).Scan(target, []*types.Probe{p}, false)
+		})
+	}
+	if err := replay(c); err != nil {
+		whole := recordedCase(p, results, exchanges, 0)
+		if err := replay(whole); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: recorded case does not replay: %v\n", err)
+		} else {
+			fmt.Fprintln(os.Stderr, "Trimmed bodies changed the result; recording them whole")
+			c = whole
+		}
+	}
+
+	outcome := "no match"
+	if c.Match {
+		outcome = "match"
+	}
+	fmt.Fprintf(os.Stderr, "Recorded %s against %s: %s, %d responses\n", p.Name, target, outcome, len(c.Responses))
+
+	switch {
+	case recordOut == "":
+		data, err := fixture.Marshal(fixture.Fixture{Probe: p.Name, Cases: []fixture.Case{c}})
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	case existing != nil:
+		return appendCase(recordOut, c)
+	default:
+		data, err := fixture.Marshal(fixture.Fixture{Probe: p.Name, Cases: []fixture.Case{c}})
+		if err != nil {
+			return err
+		}
+		header := fmt.Sprintf("# Golden-file fixtures for the %s probe, recorded with julius record.\n", p.Name)
+		return os.WriteFile(recordOut, append([]byte(header), data...), 0o644)
+	}
+}
+
+// recordedCase builds the fixture case for a recording: it expects the
+// probe's live result and serves the recorded responses, keyed by path. A
+// path requested twice keeps its first response.
+func recordedCase(p *types.Probe, results []types.Result, exchanges []scanner.Exchange, maxBody int) fixture.Case {
+	c := fixture.Case{Name: recordCase, Match: len(results) > 0, Responses: make(map[string]fixture.Response)}
+	if c.Name == "" {
+		c.Name = "negative"
+		if c.Match {
+			c.Name = "positive"
+		}
+	}
+	if c.Match && results[0].Auth != types.AuthUnknown {
+		c.Auth = results[0].Auth
+	}
+
+	headers := fixture.RecordedHeaders(p)
+	for _, ex := range exchanges {
+		path := ex.Path
+		if path == "" {
+			path = "/"
+		}
+		if _, ok := c.Responses[path]; ok {
+			continue
+		}
+		c.Responses[path] = fixture.NewResponse(ex.Method, ex.Status, ex.Header, ex.Body, headers, maxBody)
+	}
+	return c
+}
+
+// appendCase adds c to the end of the cases list in the fixture file at path.
+// Hand-written comments are kept, so the file is appended to rather than
+// re-encoded; if the result does not parse with the new case last, the file
+// is restored.
+func appendCase(path string, c fixture.Case) error {
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	item, err := fixture.MarshalCase(c)
+	if err != nil {
+		return err
+	}
+
+	data := slices.Clone(original)
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		data = append(data, '\n')
+	}
+	data = append(append(data, '\n'), item...)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+
+	fx, err := fixture.ReadFile(path)
+	if err == nil && (len(fx.Cases) == 0 || fx.Cases[len(fx.Cases)-1].Name != c.Name) {
+		err = fmt.Errorf("cases is not the last key")
+	}
+	if err != nil {
+		_ = os.WriteFile(path, original, 0o644)
+		return fmt.Errorf("appending to %s: %w", path, err)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(recordCmd)
+	recordCmd.Flags().StringVar(&recordProbe, "probe", "", "Name of the probe to record (required)")
+	recordCmd.Flags().StringVar(&recordCase, "case", "", "Case name (default: positive or negative, from the result)")
+	recordCmd.Flags().StringVar(&recordOut, "out", "", "Fixture file to write (default: stdout)")
+	recordCmd.Flags().BoolVar(&recordAppend, "append", false, "Add the case to the existing fixture file at --out")
+	recordCmd.Flags().IntVar(&recordMaxBody, "max-body", fixture.DefaultMaxBody, "Trim recorded bodies larger than this many bytes (0 keeps them whole)")
+	recordCmd.Flags().StringArrayVarP(&customHeaders, "header", "H", nil, "Custom HTTP header sent to the target (e.g., \"Authorization: Bearer token\"). Can be specified multiple times")
+	_ = recordCmd.MarkFlagRequired("probe")
+}
@@ -16,6 +16,8 @@ import (
 var (
 	outputFormat       string
 	probesDir          string
+	extraProbes        []string
+	strictProbes       bool
 	timeout            int
 	concurrency        int
 	verbose            bool
@@ -27,6 +29,10 @@ var (
 	caCertFile         string
 )
 
+// annotationNoBanner marks commands whose stdout is a machine-readable document
+// (templates, schemas) that the banner would corrupt.
+const annotationNoBanner = "julius/no-banner"
+
 var rootCmd = &cobra.Command{
 	Use:   "julius",
 	Short: "Julius - LLM Service Fingerprinting Tool",
@@ -34,15 +40,17 @@ var rootCmd = &cobra.Command{
 and analyzing responses. It helps identify LLM platforms and available models.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		useColor := isColorEnabled(noColor)
-		if showBanner && !quiet && outputFormat == "table" {
+		if showBanner && !quiet && outputFormat == "table" && cmd.Annotations[annotationNoBanner] == "" {
 			printBanner(useColor)
 		}
 	},
 }
 
 func init() {
-	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, jsonl)")
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "table", "Output format (table, json, jsonl, html)")
 	rootCmd.PersistentFlags().StringVarP(&probesDir, "probes-dir", "p", "", "Override probe definitions directory")
+	rootCmd.PersistentFlags().StringArrayVar(&extraProbes, "extra-probes", nil, "Directory of probes to layer over the embedded set (or --probes-dir); same-named probes override. Can be specified multiple times")
+	rootCmd.PersistentFlags().BoolVar(&strictProbes, "strict-probes", false, "Fail on probe files with unknown keys instead of ignoring them")
 	rootCmd.PersistentFlags().IntVarP(&timeout, "timeout", "t", 5, "HTTP timeout in seconds")
 	rootCmd.PersistentFlags().IntVarP(&concurrency, "concurrency", "c", scanner.DefaultConcurrency, "Maximum concurrent probe requests per target")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
@@ -55,15 +63,37 @@ func init() {
 }
 
 func Run() error {
+	rootCmd.Version = version()
 	return rootCmd.Execute()
 }
 
-// loadProbes loads probe definitions from the configured directory or embedded filesystem
+// sourceEmbedded labels probes shipped in the binary.
+const sourceEmbedded = "embedded"
+
+// loadProbes loads probe definitions from the configured directory, or from the
+// embedded filesystem with any installed probe pack over it, then layers each
+// --extra-probes directory on top.
 func loadProbes() ([]*types.Probe, error) {
+	var sources []probe.Source
 	if probesDir != "" {
-		return probe.LoadProbesFromDir(probesDir)
+		sources = append(sources, probe.DirSource(probesDir))
+	} else {
+		sources = append(sources, probe.Source{Name: sourceEmbedded, FS: probes.EmbeddedProbes, Dir: "."})
+		installed, ok, err := installedPackSource()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			sources = append(sources, installed)
+		}
+	}
+	for _, dir := range extraProbes {
+		sources = append(sources, probe.DirSource(dir))
+	}
+	for i := range sources {
+		sources[i].Strict = strictProbes
 	}
-	return probe.LoadProbesFromFS(probes.EmbeddedProbes, ".")
+	return probe.LoadLayers(sources...)
 }
 
 // buildTLSConfig constructs a TLS configuration based on the configured flags.
@@ -1,10 +1,19 @@
 package runner
 
 import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/praetorian-inc/julius/pkg/fixture"
+	"github.com/praetorian-inc/julius/pkg/output"
+	"github.com/praetorian-inc/julius/pkg/probe"
 	"github.com/praetorian-inc/julius/pkg/rules"
 	"github.com/praetorian-inc/julius/pkg/types"
 	"github.com/stretchr/testify/assert"
@@ -34,6 +43,37 @@ func TestValidateProbe_RequiresMatchRule(t *testing.T) {
 	assert.NotEmpty(t, validateProbe(p), "a request with no match rules should be invalid")
 }
 
+func TestValidateProbe_AuthAnnotation(t *testing.T) {
+	p := &types.Probe{
+		Name:     "auth",
+		Requests: []types.Request{{Path: "/", Auth: "public", RawMatch: []rules.RawRule{{Type: "status", Value: 200}}}},
+	}
+	errs := validateProbe(p)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "auth must be one of open, auth-required, unknown")
+
+	p.Requests[0].Auth = types.AuthOpen
+	assert.Empty(t, validateProbe(p))
+}
+
+func TestValidateProbe_RejectsUntypedExtra(t *testing.T) {
+	p := &types.Probe{
+		Name:     "bad-extra",
+		Requests: []types.Request{{Path: "/", RawMatch: []rules.RawRule{{Type: "status", Value: 200}}}},
+		Augustus: &types.AugustusConfig{
+			Generator: "mcp",
+			ConfigTemplate: types.GeneratorConfig{
+				Endpoint: "$TARGET",
+				Extra:    map[string]any{"transport": 1, "verify_tls": "no"},
+			},
+		},
+	}
+	errs := validateProbe(p)
+	require.Len(t, errs, 2)
+	assert.Contains(t, errs[0], "augustus: extra.transport")
+	assert.Contains(t, errs[1], "augustus: extra.verify_tls")
+}
+
 func TestBuildTLSConfig_NilWhenNoFlagsSet(t *testing.T) {
 	// Save original values
 	origInsecure := insecureSkipVerify
@@ -237,3 +277,423 @@ requests:
 	require.NotEmpty(t, probes, "loadProbes should return probes from directory")
 	assert.Equal(t, "test-probe", probes[0].Name)
 }
+
+func TestParseOutputFiles(t *testing.T) {
+	fileOutputs, err := parseOutputFiles([]string{"jsonl:results.jsonl", "html:report.html"})
+	require.NoError(t, err)
+	require.Len(t, fileOutputs, 2)
+	assert.Equal(t, "jsonl", fileOutputs[0].Format)
+	assert.Equal(t, "report.html", fileOutputs[1].Path)
+
+	_, err = parseOutputFiles([]string{"jsonl:results.jsonl", "bogus"})
+	assert.Error(t, err)
+}
+
+func TestRedactHeaders(t *testing.T) {
+	redacted := redactHeaders(map[string]string{
+		"Authorization": "Bearer secret",
+		"X-Api-Key":     "abc123",
+		"Cookie":        "session=1",
+		"User-Agent":    "julius",
+	})
+	assert.Equal(t, "[REDACTED]", redacted["Authorization"])
+	assert.Equal(t, "[REDACTED]", redacted["X-Api-Key"])
+	assert.Equal(t, "[REDACTED]", redacted["Cookie"])
+	assert.Equal(t, "julius", redacted["User-Agent"])
+	assert.Nil(t, redactHeaders(nil))
+}
+
+func TestSplitBasePaths(t *testing.T) {
+	assert.Equal(t, []string{"/api", "/proxy"}, splitBasePaths(" /api, ,/proxy "))
+	assert.Nil(t, splitBasePaths(""))
+}
+
+func TestRunValidate_ExtendsCycle(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	const request = "requests:\n  - path: /\n    match:\n      - type: status\n        value: 200\n"
+	write("base.yaml", "name: base\n"+request)
+	write("child.yaml", "name: child\nextends: base\n")
+	require.NoError(t, runValidate(nil, []string{dir}), "inherited requests satisfy validation")
+
+	write("a.yaml", "name: a\nextends: b\n"+request)
+	write("b.yaml", "name: b\nextends: a\n"+request)
+	err := runValidate(nil, []string{dir})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 errors")
+}
+
+func TestRunLint(t *testing.T) {
+	require.NoError(t, runLint(nil, nil), "embedded probes lint clean")
+
+	dir := t.TempDir()
+	const probe = "name: dup\ncategory: self-hosted\nport_hint: 8000\nrequests:\n  - path: %s\n    match:\n      - type: status\n        value: 200\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte(fmt.Sprintf(probe, "/a")), 0o644))
+	require.NoError(t, runLint(nil, []string{dir}))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte(fmt.Sprintf(probe, "/b")), 0o644))
+	assert.EqualError(t, runLint(nil, []string{dir}), "lint found errors")
+}
+
+func TestRunLint_AgainstEmbedded(t *testing.T) {
+	dir := t.TempDir()
+	// Extends a shipped probe and repeats its /v1/models request verbatim.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "my-llm.yaml"), []byte(`name: my-llm
+extends: openai-compatible
+category: self-hosted
+port_hint: 8000
+specificity: 80
+requests:
+  - path: /v1/models
+    match:
+      - type: status
+        value: 200
+      - type: content-type
+        value: application/json
+      - type: body.contains
+        value: '"object"'
+      - type: body.contains
+        value: '"data"'
+`), 0o644))
+
+	t.Cleanup(func() { lintFailOnWarning = false })
+	require.NoError(t, runLint(nil, []string{dir}), "shipped probes resolve as parents; warnings pass")
+	lintFailOnWarning = true
+	assert.EqualError(t, runLint(nil, []string{dir}), "lint found warnings", "the request shipped in openai-compatible is reported")
+}
+
+func TestRunTest(t *testing.T) {
+	probeDir, fixtureDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(probeDir, "private.yaml"), []byte(`name: private-llm
+category: self-hosted
+port_hint: 9000
+requests:
+  - path: /api/info
+    match:
+      - type: status
+        value: 200
+      - type: body.contains
+        value: '"private-llm"'
+`), 0o644))
+	writeFixture := func(content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(fixtureDir, "private.yaml"), []byte(content), 0o644))
+	}
+	const cases = `probe: private-llm
+cases:
+  - name: positive
+    match: true
+    responses:
+      /api/info:
+        body: '{"service":"private-llm"}'
+  - name: negative
+    match: %v
+    responses:
+      /api/info:
+        body: '{"service":"other"}'
+`
+	t.Cleanup(func() { probesDir, fixturesDir = "", "" })
+	probesDir, fixturesDir = probeDir, fixtureDir
+
+	writeFixture(fmt.Sprintf(cases, false))
+	require.NoError(t, runTest(nil, nil))
+
+	writeFixture(fmt.Sprintf(cases, true))
+	assert.EqualError(t, runTest(nil, nil), "1 of 2 fixture cases failed")
+
+	writeFixture("probe: missing\ncases: []\n")
+	assert.EqualError(t, runTest(nil, nil), "1 of 1 fixture cases failed", "unknown probes fail")
+
+	writeFixture("probe: private-llm\ncases:\n  - name: typo\n    mach: true\n")
+	assert.ErrorContains(t, runTest(nil, nil), "parsing", "unknown fixture keys are rejected")
+}
+
+func TestRunRecord(t *testing.T) {
+	models := make([]string, 50)
+	for i := range models {
+		models[i] = fmt.Sprintf(`{"id":"model-%d","object":"model","owned_by":"vllm"}`, i)
+	}
+	serve := func(server string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Server", server)
+			w.Header().Set("Set-Cookie", "session=s3cr3t")
+			switch r.URL.Path {
+			case "/v1/models":
+				_, _ = fmt.Fprintf(w, `{"object":"list","data":[%s]}`, strings.Join(models, ","))
+			case "/version":
+				_, _ = io.WriteString(w, `{"version":"0.6.3","api_key":"sk-live-0123456789abcdef"}`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	}
+	vllm, nginx := serve("uvicorn"), serve("nginx")
+	defer vllm.Close()
+	defer nginx.Close()
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "vllm.yaml")
+	t.Cleanup(func() {
+		recordProbe, recordCase, recordOut, recordAppend, recordMaxBody = "", "", "", false, fixture.DefaultMaxBody
+		fixturesDir = ""
+	})
+	recordProbe, recordOut, recordMaxBody = "vllm", out, 1024
+
+	require.NoError(t, runRecord(nil, []string{vllm.URL}))
+	assert.ErrorContains(t, runRecord(nil, []string{vllm.URL}), "pass --append")
+
+	recordAppend = true
+	assert.ErrorContains(t, runRecord(nil, []string{vllm.URL}), `already has a case named "positive"`)
+	recordCase = "negative-nginx"
+	require.NoError(t, runRecord(nil, []string{nginx.URL}))
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "# Golden-file fixtures for the vllm probe"))
+	assert.NotContains(t, string(data), "sk-live", "secrets are scrubbed")
+	assert.NotContains(t, string(data), "Set-Cookie", "only headers the probe inspects are kept")
+	assert.NotContains(t, string(data), "model-3", "long model lists are trimmed")
+
+	fx, err := fixture.ReadFile(out)
+	require.NoError(t, err)
+	require.Len(t, fx.Cases, 2)
+	assert.True(t, fx.Cases[0].Match)
+	assert.False(t, fx.Cases[1].Match)
+
+	fixturesDir = dir
+	assert.NoError(t, runTest(nil, nil), "the recorded fixture replays")
+}
+
+func TestRunNew(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/models":
+			_, _ = io.WriteString(w, `{"object":"list","data":[{"id":"m","object":"model","owned_by":"x"}]}`)
+		case "/api/info":
+			_, _ = io.WriteString(w, `{"build_hash":"abc","gpu_layers":3}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"detail":"Not Found"}`)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	out := filepath.Join(dir, "my-llm.yaml")
+	t.Cleanup(func() { newFrom, newName, newPaths, newOut = "", "new-service", nil, "" })
+	newFrom, newName, newPaths, newOut = server.URL, "my-llm", []string{"/api/info"}, out
+
+	require.NoError(t, runNew(nil, nil))
+	assert.ErrorContains(t, runNew(nil, nil), "already exists")
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	p, err := probe.ParseProbeStrict(data)
+	require.NoError(t, err, string(data))
+	assert.Equal(t, "openai-compatible", p.Extends, "a matching generic probe becomes the parent")
+	require.NotEmpty(t, p.Requests)
+	assert.Equal(t, "/api/info", p.Requests[0].Path)
+	assert.Contains(t, string(data), `'"build_hash"'`)
+	assert.NoError(t, runValidate(nil, []string{dir}), "the draft validates")
+}
+
+func TestRunProbe_RedTeamConfigs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Server", "uvicorn")
+		switch r.URL.Path {
+		case "/v1/models":
+			_, _ = io.WriteString(w, `{"object":"list","data":[{"id":"llama-3","object":"model","owned_by":"vllm"}]}`)
+		case "/version":
+			_, _ = io.WriteString(w, `{"version":"0.6.3"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	results := filepath.Join(dir, "results.json")
+	t.Cleanup(func() {
+		filterProbes, outputFiles, garakOut, promptfooOut, augustusOut = nil, nil, "", "", ""
+		augustusFlag, quiet = false, false
+	})
+	filterProbes, outputFiles, quiet = []string{"vllm"}, []string{"json:" + results}, true
+	garakOut, promptfooOut = filepath.Join(dir, "garak"), filepath.Join(dir, "providers.yaml")
+
+	require.NoError(t, runProbe(nil, []string{server.URL}))
+	assert.FileExists(t, filepath.Join(garakOut, "garak-index.json"))
+	assert.FileExists(t, promptfooOut)
+	data, err := os.ReadFile(results)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"vllm"`)
+	assert.NotContains(t, string(data), "generator_configs", "config files do not imply --augustus")
+
+	augustusOut = garakOut
+	assert.ErrorContains(t, runProbe(nil, []string{server.URL}), "must be different directories")
+}
+
+func TestRunProbe_EnvelopeNeedsJSON(t *testing.T) {
+	t.Cleanup(func() { envelopeFlag, outputFiles = false, nil })
+	envelopeFlag = true
+
+	assert.ErrorContains(t, runProbe(nil, []string{"http://127.0.0.1:1"}), "--envelope only applies to json output")
+
+	outputFiles = []string{"jsonl:results.jsonl"}
+	assert.ErrorContains(t, runProbe(nil, []string{"http://127.0.0.1:1"}), "--envelope only applies to json output")
+
+	assert.True(t, hasJSONOutput([]output.FileOutput{{Format: "jsonl"}, {Format: "json"}}), "a json file output is enough")
+}
+
+func TestLoadSelectedProbes(t *testing.T) {
+	t.Cleanup(func() { filterCategories, filterExclude, filterTags = nil, nil, nil })
+
+	filterCategories = []string{"mcp"}
+	filterExclude = []string{"*-sse"}
+	selected, err := loadSelectedProbes()
+	require.NoError(t, err)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "mcp-server", selected[0].Name)
+
+	filterCategories, filterExclude = nil, nil
+	filterTags = []string{"openai-api"}
+	selected, err = loadSelectedProbes()
+	require.NoError(t, err)
+	var names []string
+	for _, p := range selected {
+		names = append(names, p.Name)
+	}
+	assert.Contains(t, names, "vllm", "tags are inherited through extends")
+	assert.NotContains(t, names, "ollama")
+
+	filterTags = nil
+	filterExclude = []string{"["}
+	_, err = loadSelectedProbes()
+	assert.ErrorContains(t, err, "invalid probe pattern")
+}
+
+func TestLoadProbes_ExtraProbes(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "private.yaml"), []byte(`name: private-llm
+extends: openai-compatible
+category: self-hosted
+specificity: 80
+requests:
+  - path: /internal/health
+    match:
+      - type: status
+        value: 200
+`), 0o644))
+
+	extraProbes = []string{dir}
+	t.Cleanup(func() { extraProbes = nil })
+
+	loaded, err := loadProbes()
+	require.NoError(t, err)
+
+	sources := map[string]string{}
+	var private *types.Probe
+	for _, p := range loaded {
+		sources[p.Name] = p.Source
+		if p.Name == "private-llm" {
+			private = p
+		}
+	}
+	assert.Equal(t, sourceEmbedded, sources["vllm"])
+	assert.Equal(t, dir, sources["private-llm"])
+	require.NotNil(t, private)
+	require.NotNil(t, private.Augustus, "inherits from the embedded parent")
+	assert.Equal(t, "openai", private.Augustus.Generator)
+
+	require.NoError(t, runValidate(nil, []string{dir}), "validate resolves embedded parents")
+}
+
+func TestRunValidate_UnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "typo.yaml"), []byte(`name: typo
+specifity: 90
+requests:
+  - path: /
+    match:
+      - type: status
+        value: 200
+`), 0o644))
+	assert.Error(t, runValidate(nil, []string{dir}), "unknown keys fail validation")
+
+	strictProbes = true
+	t.Cleanup(func() { strictProbes, probesDir = false, "" })
+	probesDir = dir
+	_, err := loadProbes()
+	assert.ErrorContains(t, err, `unknown key "specifity"`)
+
+	strictProbes = false
+	_, err = loadProbes()
+	assert.NoError(t, err, "lenient by default at load time")
+}
+
+func TestValidateProbe_Semantic(t *testing.T) {
+	status := []rules.RawRule{{Type: "status", Value: 200}}
+	p := &types.Probe{
+		Name: "deep",
+		Requests: []types.Request{
+			{
+				Path:   "/",
+				Method: "GET",
+				RawMatch: []rules.RawRule{
+					{Type: "body.regex", Value: "x"},
+					{Type: "status", Value: "200"},
+					{Type: "header.contains", Value: "uvicorn"},
+				},
+			},
+			{
+				Path:     "/v1/chat",
+				Method:   "POST",
+				Headers:  map[string]string{"Content-Type": "application/json", "Bad Header": "x", "X-Inject": "a\r\nb"},
+				Body:     `{"model": "test",}`,
+				RawMatch: status,
+			},
+		},
+		Models: &types.ModelsConfig{
+			Path:       "/v1/models",
+			Extract:    ".data[].id |",
+			Items:      ".data[]",
+			Fields:     map[string]string{"name": ".id", "size": ".size +"},
+			Pagination: &types.PaginationConfig{Type: "offset", Next: ".next"},
+		},
+		Augustus: &types.AugustusConfig{
+			Generator: "openapi",
+			ConfigTemplate: types.GeneratorConfig{
+				Endpoint: "$TARGET",
+				Headers:  map[string]string{"Content-Type": "application/json"},
+				Body:     `{"prompt": $PROMPT}`,
+			},
+		},
+	}
+
+	errs := validateProbe(p)
+	for _, want := range []string{
+		"request 0: rule 0: unknown rule type: body.regex",
+		"request 0: rule 1: status value must be int, got string",
+		"request 0: rule 2: header.contains requires header",
+		`request 1: header name "Bad Header" is not a valid HTTP token`,
+		"request 1: header X-Inject contains control characters",
+		"request 1: body is not valid JSON but Content-Type is application/json",
+		"models: extract: invalid jq expression",
+		"models: fields.size: invalid jq expression",
+		"models: pagination.type must be 'cursor', 'page' or 'link', got 'offset'",
+		"augustus: generator must be one of openai, ollama, rest, mcp, got 'openapi'",
+		"augustus: body is not valid JSON but Content-Type is application/json",
+	} {
+		assert.True(t, slices.ContainsFunc(errs, func(e string) bool { return strings.HasPrefix(e, want) }), "missing %q in %v", want, errs)
+	}
+	assert.Len(t, errs, 11)
+}
+
+func TestValidateModels_ItemsAndFields(t *testing.T) {
+	assert.Empty(t, validateModels(&types.ModelsConfig{Extract: ".data[].id", Items: ".data[]", Fields: map[string]string{"name": ".id"}}))
+	assert.Equal(t, []string{"models: items requires fields"}, validateModels(&types.ModelsConfig{Extract: ".data[].id", Items: ".data[]"}))
+	assert.Equal(t, []string{"models: fields requires items"}, validateModels(&types.ModelsConfig{Extract: ".data[].id", Fields: map[string]string{"name": ".id"}}))
+}
@@ -0,0 +1,34 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/praetorian-inc/julius/pkg/probe"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schema for probe files",
+	Long: `Print a JSON Schema (draft 2020-12) describing probe YAML files, for editor
+autocompletion and linting. Unknown keys are rejected, as julius validate and
+--strict-probes reject them.
+
+Example:
+  julius schema > probe.schema.json`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNoBanner: "true"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := json.MarshalIndent(probe.JSONSchema(), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
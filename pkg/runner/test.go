@@ -0,0 +1,146 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/praetorian-inc/julius/pkg/fixture"
+	"github.com/praetorian-inc/julius/pkg/scanner"
+	"github.com/praetorian-inc/julius/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var fixturesDir string
+
+var testCmd = &cobra.Command{
+	Use:   "test --fixtures <directory>",
+	Short: "Test probes against golden fixture files",
+	Long: `Run probes against canned HTTP responses. Each fixture file names a probe and
+lists cases; every case is served from a local server, the probe is scanned
+against it, and the case passes when the probe matches (or does not) as the
+case says. The fixture format is the one the shipped probes are tested with in
+pkg/scanner/testdata/fixtures.
+
+Probes are loaded as for julius probe, so --probes-dir and --extra-probes select
+the probes under test.
+
+Examples:
+  julius test --probes-dir ./my-probes --fixtures ./my-fixtures
+  julius test --extra-probes ./my-probes --fixtures ./my-fixtures -o json`,
+	Args: cobra.NoArgs,
+	RunE: runTest,
+}
+
+// caseResult is the outcome of one fixture case.
+type caseResult struct {
+	Probe  string `json:"probe"`
+	Case   string `json:"case"`
+	File   string `json:"file"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+}
+
+func runTest(cmd *cobra.Command, args []string) error {
+	fixtures, err := fixture.Load(fixturesDir)
+	if err != nil {
+		return err
+	}
+	if len(fixtures) == 0 {
+		return fmt.Errorf("no fixtures found in %s", fixturesDir)
+	}
+
+	loaded, err := loadProbes()
+	if err != nil {
+		return fmt.Errorf("loading probes: %w", err)
+	}
+	byName := make(map[string]*types.Probe, len(loaded))
+	for _, p := range loaded {
+		byName[p.Name] = p
+	}
+
+	tlsConfig, err := buildTLSConfig()
+	if err != nil {
+		return err
+	}
+	opts := []scanner.Option{
+		scanner.WithTimeout(time.Duration(timeout) * time.Second),
+		scanner.WithConcurrency(concurrency),
+		scanner.WithMaxResponseSize(maxResponseSize),
+		scanner.WithTLSConfig(tlsConfig),
+	}
+
+	results := runFixtures(fixtures, byName, opts)
+
+	failed := 0
+	for _, r := range results {
+		if !r.Passed {
+			failed++
+		}
+	}
+
+	if outputFormat == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range results {
+			switch {
+			case !r.Passed:
+				fmt.Printf("FAIL: %s/%s - %s\n", r.Probe, r.Case, r.Error)
+			case !quiet:
+				fmt.Printf("PASS: %s/%s\n", r.Probe, r.Case)
+			}
+		}
+		fmt.Printf("\nTest complete: %d passed, %d failed\n", len(results)-failed, failed)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d fixture cases failed", failed, len(results))
+	}
+	return nil
+}
+
+// runFixtures runs every case of every fixture against its probe. A fixture
+// naming an unknown probe, or with no cases, is reported as one failed case.
+// Each case gets a fresh scanner so cached responses never leak between cases.
+func runFixtures(fixtures []fixture.Fixture, byName map[string]*types.Probe, opts []scanner.Option) []caseResult {
+	var results []caseResult
+	for _, fx := range fixtures {
+		p, ok := byName[fx.Probe]
+		if !ok || len(fx.Cases) == 0 {
+			msg := fmt.Sprintf("unknown probe %q", fx.Probe)
+			if ok {
+				msg = "fixture has no cases"
+			}
+			results = append(results, caseResult{Probe: fx.Probe, Case: "*", File: fx.Path, Error: msg})
+			continue
+		}
+
+		for i, c := range fx.Cases {
+			r := caseResult{Probe: fx.Probe, Case: c.Name, File: fx.Path}
+			if r.Case == "" {
+				r.Case = fmt.Sprintf("case-%d", i)
+			}
+			err := c.Run(func(target string) []types.Result {
+				return scanner.NewScanner(opts...).Scan(target, []*types.Probe{p}, false)
+			})
+			if err != nil {
+				r.Error = err.Error()
+			} else {
+				r.Passed = true
+			}
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+func init() {
+	rootCmd.AddCommand(testCmd)
+	testCmd.Flags().StringVar(&fixturesDir, "fixtures", "", "Directory of fixture files (required)")
+	_ = testCmd.MarkFlagRequired("fixtures")
+}
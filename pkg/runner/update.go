@@ -0,0 +1,196 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/praetorian-inc/julius/pkg/pack"
+	"github.com/praetorian-inc/julius/pkg/probe"
+	"github.com/praetorian-inc/julius/pkg/types"
+	"github.com/praetorian-inc/julius/probes"
+	"github.com/spf13/cobra"
+)
+
+// ProbePackPublicKey is the base64 ed25519 key probe packs are verified with
+// when --public-key is not given, set at build time with
+// -ldflags "-X github.com/praetorian-inc/julius/pkg/runner.ProbePackPublicKey=...".
+var ProbePackPublicKey = ""
+
+var (
+	packSource    string
+	packPublicKey string
+	packDir       string
+	packDowngrade bool
+)
+
+var probesCmd = &cobra.Command{
+	Use:   "probes",
+	Short: "Manage installed probe packs",
+}
+
+var probesUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Download and install a signed probe pack",
+	Long: `Download a probe pack (a gzipped tarball of probe YAML files plus a signed
+manifest), verify its ed25519 signature and the hash of every file, validate
+every probe, and install it into the user probe directory. Installed packs are
+layered over the embedded probes on every run, so new services can be detected
+without a new binary. --probes-dir ignores the installed pack.
+
+A pack is only installed over an older version, so an old signed pack cannot
+be replayed to roll back detections; pass --allow-downgrade to install it
+anyway.
+
+Example:
+  julius probes update --source https://example.com/julius-probes.tar.gz`,
+	Args: cobra.NoArgs,
+	RunE: runProbesUpdate,
+}
+
+// userPackDir is where probes update installs packs by default.
+func userPackDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating user config directory: %w", err)
+	}
+	return filepath.Join(dir, "julius", "probes"), nil
+}
+
+// installedPackSource returns the installed probe pack as a probe source, or
+// false when none is installed.
+func installedPackSource() (probe.Source, bool, error) {
+	dir, err := userPackDir()
+	if err != nil {
+		return probe.Source{}, false, nil
+	}
+	version, err := pack.InstalledVersion(dir)
+	if err != nil || version == "" {
+		return probe.Source{}, false, err
+	}
+	src := probe.DirSource(dir)
+	src.Name = "pack " + version
+	return src, true, nil
+}
+
+func runProbesUpdate(cmd *cobra.Command, args []string) error {
+	keyText := packPublicKey
+	if keyText == "" {
+		keyText = ProbePackPublicKey
+	}
+	if keyText == "" {
+		return fmt.Errorf("no probe pack public key: pass --public-key")
+	}
+	key, err := pack.ParsePublicKey(keyText)
+	if err != nil {
+		return err
+	}
+
+	dir := packDir
+	if dir == "" {
+		if dir, err = userPackDir(); err != nil {
+			return err
+		}
+	}
+
+	tlsConfig, err := buildTLSConfig()
+	if err != nil {
+		return fmt.Errorf("building TLS config: %w", err)
+	}
+	client := &http.Client{
+		Timeout:   time.Duration(timeout) * time.Second,
+		Transport: &http.Transport{Proxy: http.ProxyFromEnvironment, TLSClientConfig: tlsConfig},
+	}
+
+	p, err := pack.Fetch(context.Background(), client, packSource, key)
+	if err != nil {
+		return err
+	}
+	if err := validatePack(p); err != nil {
+		return err
+	}
+
+	previous, err := pack.InstalledVersion(dir)
+	if err != nil {
+		return err
+	}
+	if previous != "" && !packDowngrade {
+		switch c := pack.CompareVersions(p.Manifest.Version, previous); {
+		case c == 0:
+			fmt.Printf("Probe pack %s is already installed in %s\n", previous, dir)
+			return nil
+		case c < 0:
+			return fmt.Errorf("probe pack %s is older than installed pack %s: pass --allow-downgrade to install it", p.Manifest.Version, previous)
+		}
+	}
+	if err := p.Install(dir); err != nil {
+		return fmt.Errorf("installing probe pack: %w", err)
+	}
+
+	if previous != "" && previous != p.Manifest.Version {
+		fmt.Printf("Updated probe pack %s -> %s (%d probes) in %s\n", previous, p.Manifest.Version, len(p.Files), dir)
+	} else {
+		fmt.Printf("Installed probe pack %s (%d probes) in %s\n", p.Manifest.Version, len(p.Files), dir)
+	}
+	return nil
+}
+
+// validatePack applies julius validate to every probe in the pack, resolving
+// extends against the embedded probes the pack is layered over.
+func validatePack(p *pack.Pack) error {
+	embedded, err := probe.LoadProbesFromFS(probes.EmbeddedProbes, ".")
+	if err != nil {
+		return fmt.Errorf("loading embedded probes: %w", err)
+	}
+	byName := make(map[string]*types.Probe, len(embedded)+len(p.Files))
+	for _, ep := range embedded {
+		byName[ep.Name] = ep
+	}
+
+	parsed := make(map[string]*types.Probe, len(p.Files))
+	var problems []string
+	for name, data := range p.Files {
+		pp, err := probe.ParseProbe(data)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s - invalid YAML: %v", name, err))
+			continue
+		}
+		parsed[name] = pp
+		byName[pp.Name] = pp
+		unknown, _ := probe.UnknownKeys(data)
+		for _, e := range unknown {
+			problems = append(problems, fmt.Sprintf("%s - %s", name, e))
+		}
+	}
+	for name, pp := range parsed {
+		resolved, err := probe.Resolve(pp, byName)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s - %v", name, err))
+			continue
+		}
+		for _, e := range validateProbe(resolved) {
+			problems = append(problems, fmt.Sprintf("%s - %s", name, e))
+		}
+	}
+
+	if len(problems) > 0 {
+		slices.Sort(problems)
+		return fmt.Errorf("probe pack %s failed validation:\n  %s", p.Manifest.Version, strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(probesCmd)
+	probesCmd.AddCommand(probesUpdateCmd)
+	probesUpdateCmd.Flags().StringVar(&packSource, "source", "", "URL of the probe pack tarball")
+	probesUpdateCmd.Flags().StringVar(&packPublicKey, "public-key", "", "Base64 ed25519 public key to verify the pack with (default: the key built into julius)")
+	probesUpdateCmd.Flags().StringVar(&packDir, "dir", "", "Install into this directory instead of the user probe directory")
+	probesUpdateCmd.Flags().BoolVar(&packDowngrade, "allow-downgrade", false, "Install the pack even if its version is not newer than the installed one")
+	_ = probesUpdateCmd.MarkFlagRequired("source")
+}
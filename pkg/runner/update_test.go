@@ -0,0 +1,109 @@
+package runner
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/pack"
+)
+
+func TestRunProbesUpdate(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	packs := map[string][]byte{}
+	build := func(name string, files map[string][]byte) {
+		var buf bytes.Buffer
+		require.NoError(t, pack.Write(&buf, name, files, priv))
+		packs["/"+name+".tar.gz"] = buf.Bytes()
+	}
+	build("v1", map[string][]byte{"new-llm.yaml": []byte(`name: new-llm
+extends: openai-compatible
+description: A product released after this binary
+category: self-hosted
+specificity: 80
+requests:
+  - path: /new/health
+    match:
+      - type: status
+        value: 200
+`)})
+	build("v0", map[string][]byte{"old-llm.yaml": []byte(`name: old-llm
+description: A product an older pack knew about
+category: self-hosted
+specificity: 80
+requests:
+  - path: /old/health
+    match:
+      - type: status
+        value: 200
+`)})
+	build("broken", map[string][]byte{"bad.yaml": []byte("name: bad\nrequests:\n  - path: /\n")})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, ok := packs[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	packPublicKey = base64.StdEncoding.EncodeToString(pub)
+	t.Cleanup(func() { packSource, packPublicKey, packDowngrade = "", "", false })
+
+	packSource = server.URL + "/broken.tar.gz"
+	err = runProbesUpdate(nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad.yaml - request 0: at least one match rule is required")
+
+	_, installed, err := installedPackSource()
+	require.NoError(t, err)
+	assert.False(t, installed, "an invalid pack is not installed")
+
+	packSource = server.URL + "/v1.tar.gz"
+	require.NoError(t, runProbesUpdate(nil, nil))
+
+	loaded, err := loadProbes()
+	require.NoError(t, err)
+	sources := map[string]string{}
+	for _, p := range loaded {
+		sources[p.Name] = p.Source
+	}
+	assert.Equal(t, "pack v1", sources["new-llm"], "the installed pack is layered over the embedded set")
+	assert.Equal(t, sourceEmbedded, sources["ollama"])
+
+	require.NoError(t, runProbesUpdate(nil, nil), "reinstalling the same version is a no-op")
+
+	packSource = server.URL + "/v0.tar.gz"
+	err = runProbesUpdate(nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "probe pack v0 is older than installed pack v1")
+	src, _, err := installedPackSource()
+	require.NoError(t, err)
+	assert.Equal(t, "pack v1", src.Name, "an older pack is not replayed over a newer one")
+
+	packDowngrade = true
+	require.NoError(t, runProbesUpdate(nil, nil))
+	src, _, err = installedPackSource()
+	require.NoError(t, err)
+	assert.Equal(t, "pack v0", src.Name, "--allow-downgrade installs an older pack")
+	packDowngrade = false
+
+	packSource = server.URL + "/v1.tar.gz"
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	packPublicKey = base64.StdEncoding.EncodeToString(otherPub)
+	assert.ErrorIs(t, runProbesUpdate(nil, nil), pack.ErrSignature)
+}
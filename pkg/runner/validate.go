@@ -1,13 +1,18 @@
 package runner
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/itchyny/gojq"
 	"github.com/praetorian-inc/julius/pkg/probe"
 	"github.com/praetorian-inc/julius/pkg/types"
+	"github.com/praetorian-inc/julius/probes"
 	"github.com/spf13/cobra"
 )
 
@@ -15,7 +20,13 @@ var validateCmd = &cobra.Command{
 	Use:   "validate [directory]",
 	Short: "Validate probe definition files",
 	Long: `Validate probe definition YAML files in a directory.
-Checks each file for proper YAML syntax and required fields.
+Checks each file for proper YAML syntax, unknown keys (typos such as
+specifity: that would otherwise be silently ignored) and required fields, after resolving
+extends: against the other probes in the directory and the embedded set
+(reporting unknown parents and inheritance cycles). Every match rule is compiled,
+every models jq expression parsed and the augustus generator checked, and
+headers and JSON bodies must be well-formed, so nothing fails silently at scan
+time.
 
 Example:
   julius validate ./probes`,
@@ -35,6 +46,24 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	validCount := 0
 	errorCount := 0
 
+	// Parse every file first so extends: can be resolved against the others.
+	// Probes in the directory shadow the embedded ones, which remain available
+	// as parents for directories meant for --extra-probes.
+	type parsedFile struct {
+		filename string
+		probe    *types.Probe
+		unknown  []string // keys no probe field reads
+	}
+	var parsed []parsedFile
+	byName := make(map[string]*types.Probe)
+	embedded, err := probe.LoadProbesFromFS(probes.EmbeddedProbes, ".")
+	if err != nil {
+		return fmt.Errorf("loading embedded probes: %w", err)
+	}
+	for _, p := range embedded {
+		byName[p.Name] = p
+	}
+
 	for _, entry := range entries {
 		if entry.IsDir() {
 			continue
@@ -62,17 +91,32 @@ func runValidate(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		errs := validateProbe(p)
+		// ParseProbe succeeded, so the document parses.
+		unknown, _ := probe.UnknownKeys(data)
+		parsed = append(parsed, parsedFile{filename: filename, probe: p, unknown: unknown})
+		byName[p.Name] = p
+	}
+
+	for _, f := range parsed {
+		p, err := probe.Resolve(f.probe, byName)
+		if err != nil {
+			fmt.Printf("ERROR: %s - %v\n", f.filename, err)
+			hasErrors = true
+			errorCount++
+			continue
+		}
+
+		errs := append(f.unknown, validateProbe(p)...)
 		if len(errs) > 0 {
 			for _, e := range errs {
-				fmt.Printf("ERROR: %s - %s\n", filename, e)
+				fmt.Printf("ERROR: %s - %s\n", f.filename, e)
 			}
 			hasErrors = true
 			errorCount++
 			continue
 		}
 
-		fmt.Printf("OK: %s\n", filename)
+		fmt.Printf("OK: %s\n", f.filename)
 		validCount++
 	}
 
@@ -112,11 +156,140 @@ func validateProbe(p *types.Probe) []string {
 		if len(req.RawMatch) == 0 {
 			errors = append(errors, fmt.Sprintf("request %d: at least one match rule is required", i))
 		}
+		for j, raw := range req.RawMatch {
+			if _, err := raw.ToRule(); err != nil {
+				errors = append(errors, fmt.Sprintf("request %d: rule %d: %v", i, j, err))
+			}
+		}
+		if req.Method != "" && !isHTTPToken(req.Method) {
+			errors = append(errors, fmt.Sprintf("request %d: method %q is not a valid HTTP method", i, req.Method))
+		}
+		errors = append(errors, checkHeaders(fmt.Sprintf("request %d", i), req.Headers)...)
+		errors = append(errors, checkJSONBody(fmt.Sprintf("request %d", i), req.Headers, req.Body)...)
+		if req.Type != "" && req.Type != types.RequestTypeHTTP && req.Type != types.RequestTypeSSE {
+			errors = append(errors, fmt.Sprintf("request %d: type must be '%s' or '%s', got '%s'", i, types.RequestTypeHTTP, types.RequestTypeSSE, req.Type))
+		}
+		if req.Auth != "" && !slices.Contains(types.AuthPostures, req.Auth) {
+			errors = append(errors, fmt.Sprintf("request %d: auth must be one of %s, got '%s'", i, strings.Join(types.AuthPostures, ", "), req.Auth))
+		}
+	}
+
+	if p.MCP != nil {
+		if t := p.MCP.GetTransport(); t != types.MCPTransportStreamableHTTP && t != types.MCPTransportSSE {
+			errors = append(errors, fmt.Sprintf("mcp: transport must be '%s' or '%s', got '%s'", types.MCPTransportStreamableHTTP, types.MCPTransportSSE, t))
+		}
+	}
+
+	if p.Models != nil {
+		errors = append(errors, validateModels(p.Models)...)
+	}
+
+	if p.Augustus != nil {
+		if !slices.Contains(types.Generators, p.Augustus.Generator) {
+			errors = append(errors, fmt.Sprintf("augustus: generator must be one of %s, got '%s'", strings.Join(types.Generators, ", "), p.Augustus.Generator))
+		}
+		cfg := p.Augustus.ConfigTemplate
+		errors = append(errors, checkHeaders("augustus", cfg.Headers)...)
+		errors = append(errors, checkJSONBody("augustus", cfg.Headers, cfg.Body)...)
+		if err := types.ValidateExtra(p.Augustus.Generator, p.Augustus.ConfigTemplate.Extra); err != nil {
+			for _, line := range strings.Split(err.Error(), "\n") {
+				errors = append(errors, "augustus: "+line)
+			}
+		}
 	}
 
 	return errors
 }
 
+// validateModels compiles every jq expression in the models section and
+// checks the request it describes.
+func validateModels(m *types.ModelsConfig) []string {
+	var errors []string
+	checkJQ := func(field, expr string) {
+		if expr == "" {
+			return
+		}
+		if _, err := gojq.Parse(expr); err != nil {
+			errors = append(errors, fmt.Sprintf("models: %s: invalid jq expression: %v", field, err))
+		}
+	}
+
+	if m.Extract == "" {
+		errors = append(errors, "models: extract is required")
+	}
+	checkJQ("extract", m.Extract)
+	checkJQ("items", m.Items)
+	// Model details are only extracted when both are set.
+	switch {
+	case m.Items != "" && len(m.Fields) == 0:
+		errors = append(errors, "models: items requires fields")
+	case m.Items == "" && len(m.Fields) > 0:
+		errors = append(errors, "models: fields requires items")
+	}
+	for _, key := range slices.Sorted(maps.Keys(m.Fields)) {
+		checkJQ("fields."+key, m.Fields[key])
+	}
+	if m.Method != "" && !isHTTPToken(m.Method) {
+		errors = append(errors, fmt.Sprintf("models: method %q is not a valid HTTP method", m.Method))
+	}
+	errors = append(errors, checkHeaders("models", m.Headers)...)
+	errors = append(errors, checkJSONBody("models", m.Headers, m.Body)...)
+
+	if pg := m.Pagination; pg != nil {
+		switch pg.Type {
+		case types.PaginationCursor, types.PaginationPage, types.PaginationLink:
+		default:
+			errors = append(errors, fmt.Sprintf("models: pagination.type must be '%s', '%s' or '%s', got '%s'", types.PaginationCursor, types.PaginationPage, types.PaginationLink, pg.Type))
+		}
+		checkJQ("pagination.next", pg.Next)
+		checkJQ("pagination.has_more", pg.HasMore)
+	}
+	return errors
+}
+
+// checkHeaders reports header names that are not HTTP tokens and values that
+// contain control characters, either of which net/http refuses to send.
+func checkHeaders(where string, headers map[string]string) []string {
+	var errors []string
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		if !isHTTPToken(name) {
+			errors = append(errors, fmt.Sprintf("%s: header name %q is not a valid HTTP token", where, name))
+		}
+		if strings.ContainsFunc(headers[name], func(r rune) bool { return r < ' ' && r != '\t' || r == 0x7f }) {
+			errors = append(errors, fmt.Sprintf("%s: header %s contains control characters", where, name))
+		}
+	}
+	return errors
+}
+
+// checkJSONBody reports a body that is not valid JSON when the headers
+// declare a JSON content type.
+func checkJSONBody(where string, headers map[string]string, body string) []string {
+	if body == "" {
+		return nil
+	}
+	for name, value := range headers {
+		if strings.EqualFold(name, "Content-Type") && strings.Contains(strings.ToLower(value), "json") && !json.Valid([]byte(body)) {
+			return []string{fmt.Sprintf("%s: body is not valid JSON but Content-Type is %s", where, value)}
+		}
+	}
+	return nil
+}
+
+// isHTTPToken reports whether s is a non-empty RFC 9110 token, the syntax of
+// header names and methods.
+func isHTTPToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r > 0x7e || r <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return false
+		}
+	}
+	return true
+}
+
 func init() {
 	rootCmd.AddCommand(validateCmd)
 }
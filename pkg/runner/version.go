@@ -0,0 +1,19 @@
+package runner
+
+import "runtime/debug"
+
+// Version is the julius release version, set at build time with
+// -ldflags "-X github.com/praetorian-inc/julius/pkg/runner.Version=v1.2.3".
+var Version = ""
+
+// version returns the build-time Version, falling back to the module version
+// recorded by `go install ...@version`, then "dev".
+func version() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
@@ -0,0 +1,345 @@
+// Package scaffold drafts a probe from the responses of a live instance.
+//
+// Each response to a common LLM path is compared with the target's answer
+// for a path it cannot have, and with what any web server sends. Whatever is
+// left (a distinctive header, a JSON key, a page title, a short banner) is
+// proposed as a candidate match rule. The draft is a starting point: nothing
+// here knows which markers other services share.
+package scaffold
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/praetorian-inc/julius/pkg/rules"
+	"github.com/praetorian-inc/julius/pkg/scanner"
+)
+
+// CommonPaths are the paths fetched from a target: model listings, version,
+// health and documentation endpoints that LLM servers commonly expose.
+var CommonPaths = []string{
+	"/",
+	"/api/config",
+	"/api/models",
+	"/api/tags",
+	"/api/v1/models",
+	"/api/v1/version",
+	"/api/version",
+	"/config",
+	"/docs",
+	"/docs.json",
+	"/health",
+	"/health/readiness",
+	"/healthz",
+	"/info",
+	"/manifest.json",
+	"/metrics",
+	"/model_info",
+	"/openapi.json",
+	"/props",
+	"/server_info",
+	"/status",
+	"/swagger.json",
+	"/v1/health/ready",
+	"/v1/metadata",
+	"/v1/models",
+	"/v2",
+	"/version",
+}
+
+// BaselinePath is requested to learn how the target answers a path it does
+// not serve, so catch-all pages and server-wide headers are recognised.
+const BaselinePath = "/julius-scaffold-baseline"
+
+// maxTokens is how many body markers are proposed per request.
+const maxTokens = 4
+
+// genericHeaders are sent by web servers, proxies and CDNs regardless of the
+// application behind them.
+var genericHeaders = []string{
+	"Accept-Ranges", "Access-Control-Allow-Credentials", "Access-Control-Allow-Headers",
+	"Access-Control-Allow-Methods", "Access-Control-Allow-Origin", "Access-Control-Expose-Headers",
+	"Access-Control-Max-Age", "Age", "Alt-Svc", "Cache-Control", "Cf-Cache-Status", "Cf-Ray",
+	"Connection", "Content-Disposition", "Content-Encoding", "Content-Language", "Content-Length",
+	"Content-Security-Policy", "Content-Type", "Date", "Etag", "Expires", "Keep-Alive",
+	"Last-Modified", "Location", "Nel", "Permissions-Policy", "Pragma", "Referrer-Policy",
+	"Report-To", "Set-Cookie", "Strict-Transport-Security", "Transfer-Encoding", "Vary", "Via",
+	"X-Cache", "X-Content-Type-Options", "X-Correlation-Id", "X-Frame-Options", "X-Request-Id",
+	"X-Served-By", "X-Xss-Protection",
+}
+
+// genericServers are Server header products that say nothing about the
+// application.
+var genericServers = []string{
+	"apache", "awselb", "caddy", "cloudflare", "cloudfront", "envoy", "gunicorn", "istio-envoy",
+	"kestrel", "microsoft-iis", "nginx", "openresty", "traefik",
+}
+
+// genericKeys are JSON keys too common across APIs to identify one.
+var genericKeys = []string{
+	"code", "created", "data", "description", "detail", "error", "errors", "id", "message",
+	"name", "object", "ok", "owned_by", "result", "status", "success", "type",
+}
+
+// Candidate is a path whose response stood out, with the rules proposed for
+// it. Markers counts the rules beyond status and content type.
+type Candidate struct {
+	Path    string
+	Status  int
+	Rules   []rules.RawRule
+	Markers int
+}
+
+// Analyze proposes match rules for every observed response that differs from
+// the baseline. Headers the baseline carries with the same value are
+// server-wide: they are proposed once, on the best candidate, rather than on
+// every request. Candidates are ordered by how many markers they found.
+func Analyze(baseline scanner.Exchange, observed []scanner.Exchange) []Candidate {
+	var (
+		candidates []Candidate
+		serverWide []rules.RawRule
+		seenWide   = make(map[string]bool)
+	)
+	for _, ex := range observed {
+		if ex.Status == http.StatusNotFound || ex.Status == http.StatusMethodNotAllowed {
+			continue
+		}
+		if ex.Status == baseline.Status && bytes.Equal(ex.Body, baseline.Body) {
+			continue // a catch-all page
+		}
+
+		c := Candidate{Path: ex.Path, Status: ex.Status}
+		c.Rules = append(c.Rules, rules.RawRule{Type: "status", Value: ex.Status})
+		if mt, _, err := mime.ParseMediaType(ex.Header.Get("Content-Type")); err == nil {
+			c.Rules = append(c.Rules, rules.RawRule{Type: "content-type", Value: mt})
+		}
+
+		for _, r := range headerRules(ex.Header) {
+			if baseline.Header != nil && baseline.Header.Get(r.Header) == ex.Header.Get(r.Header) {
+				if !seenWide[r.Header] {
+					seenWide[r.Header] = true
+					serverWide = append(serverWide, r)
+				}
+				continue
+			}
+			c.Rules = append(c.Rules, r)
+			c.Markers++
+		}
+
+		for _, token := range bodyTokens(ex.Header.Get("Content-Type"), ex.Body, baseline.Body) {
+			c.Rules = append(c.Rules, rules.RawRule{Type: "body.contains", Value: token})
+			c.Markers++
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Markers > candidates[j].Markers
+	})
+	if len(candidates) > 0 && len(serverWide) > 0 {
+		candidates[0].Rules = append(candidates[0].Rules, serverWide...)
+		candidates[0].Markers += len(serverWide)
+	}
+	return slices.DeleteFunc(candidates, func(c Candidate) bool { return c.Markers == 0 })
+}
+
+// headerRules proposes a rule for each non-generic header: the product name
+// for Server and X-Powered-By, presence for anything else, since header values
+// tend to carry versions and IDs.
+func headerRules(h http.Header) []rules.RawRule {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		if !slices.Contains(genericHeaders, http.CanonicalHeaderKey(name)) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var out []rules.RawRule
+	for _, name := range names {
+		value := ""
+		if name == "Server" || name == "X-Powered-By" {
+			value = product(h.Get(name))
+			if value == "" || slices.Contains(genericServers, strings.ToLower(value)) {
+				continue
+			}
+		}
+		out = append(out, rules.RawRule{Type: "header.contains", Header: name, Value: value})
+	}
+	return out
+}
+
+// product returns the first product token of a Server-style header:
+// "uvicorn" from "uvicorn", "Werkzeug" from "Werkzeug/3.0.1 Python/3.11".
+func product(v string) string {
+	v, _, _ = strings.Cut(strings.TrimSpace(v), " ")
+	v, _, _ = strings.Cut(v, "/")
+	return v
+}
+
+var titleRe = regexp.MustCompile(`(?is)<title[^>]*>\s*([^<]+?)\s*</title>`)
+
+// bodyTokens proposes body.contains values: JSON keys the baseline lacks, the
+// text of an HTML page title, or the first line of a short text banner.
+func bodyTokens(contentType string, body, baseline []byte) []string {
+	var v any
+	if json.Unmarshal(body, &v) == nil {
+		var tokens []string
+		for _, key := range jsonKeys(v, 2) {
+			token := strconv.Quote(key)
+			if slices.Contains(genericKeys, key) || bytes.Contains(baseline, []byte(token)) || slices.Contains(tokens, token) {
+				continue
+			}
+			tokens = append(tokens, token)
+			if len(tokens) == maxTokens {
+				break
+			}
+		}
+		return tokens
+	}
+
+	if m := titleRe.FindSubmatch(body); m != nil && !bytes.Contains(baseline, m[1]) {
+		return []string{string(m[1])}
+	}
+
+	if strings.HasPrefix(contentType, "text/plain") || contentType == "" {
+		line, _, _ := strings.Cut(strings.TrimSpace(string(body)), "\n")
+		if line = strings.TrimSpace(line); line != "" && len(line) <= 80 && !bytes.Contains(baseline, []byte(line)) {
+			return []string{line}
+		}
+	}
+	return nil
+}
+
+// jsonKeys returns object keys breadth-first down to depth levels, sorted
+// within each object, looking into the first element of arrays.
+func jsonKeys(v any, depth int) []string {
+	var keys, next []string
+	switch v := v.(type) {
+	case map[string]any:
+		names := make([]string, 0, len(v))
+		for k := range v {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+		keys = append(keys, names...)
+		if depth > 1 {
+			for _, k := range names {
+				next = append(next, jsonKeys(v[k], depth-1)...)
+			}
+		}
+	case []any:
+		if len(v) > 0 {
+			return jsonKeys(v[0], depth)
+		}
+	}
+	return append(keys, next...)
+}
+
+// Draft is everything rendered into a draft probe.
+type Draft struct {
+	Name       string
+	Target     string
+	PortHint   int
+	Extends    string   // a generic probe that already matches the target
+	Matched    []string // existing probes that match the target
+	Candidates []Candidate
+	MaxRequest int // candidates beyond this are listed in a comment
+}
+
+// Write renders d as probe YAML, laid out like the shipped probes, with the
+// fields an author must fill in marked TODO.
+func Write(w io.Writer, d Draft) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# probes/%s.yaml\n", d.Name)
+	fmt.Fprintf(&b, "# Draft generated by julius new from %s. Every rule is a candidate:\n", d.Target)
+	b.WriteString("# keep the ones only this service returns, fill in the TODOs, then run\n")
+	b.WriteString("# julius validate, julius lint and julius record before submitting.\n")
+	if len(d.Matched) > 0 {
+		fmt.Fprintf(&b, "# Already matched by: %s\n", strings.Join(d.Matched, ", "))
+	}
+	fmt.Fprintf(&b, "name: %s\n", d.Name)
+	if d.Extends != "" {
+		fmt.Fprintf(&b, "extends: %s\n", d.Extends)
+	}
+	b.WriteString("description: TODO\n")
+	b.WriteString("category: self-hosted\n")
+	fmt.Fprintf(&b, "port_hint: %d\n", d.PortHint)
+	b.WriteString("specificity: 50\n")
+	b.WriteString("api_docs: TODO\n")
+	b.WriteString("\nrequests:\n")
+
+	shown := d.Candidates
+	if d.MaxRequest > 0 && len(shown) > d.MaxRequest {
+		shown = shown[:d.MaxRequest]
+	}
+	if len(shown) == 0 {
+		b.WriteString("  # No response differed from the baseline; add requests by hand.\n")
+	}
+	for i, c := range shown {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "  # %d distinctive marker(s) on %s\n", c.Markers, c.Path)
+		b.WriteString("  - type: http\n")
+		fmt.Fprintf(&b, "    path: %s\n", quote(c.Path))
+		b.WriteString("    method: GET\n")
+		b.WriteString("    match:\n")
+		for _, r := range c.Rules {
+			fmt.Fprintf(&b, "      - type: %s\n", r.Type)
+			if r.Header != "" {
+				fmt.Fprintf(&b, "        header: %s\n", r.Header)
+			}
+			switch v := r.Value.(type) {
+			case int:
+				fmt.Fprintf(&b, "        value: %d\n", v)
+			case string:
+				fmt.Fprintf(&b, "        value: %s\n", quote(v))
+			}
+		}
+	}
+	if rest := d.Candidates[len(shown):]; len(rest) > 0 {
+		b.WriteString("\n  # Other paths that answered distinctively:\n")
+		for _, c := range rest {
+			fmt.Fprintf(&b, "  #   %s (%d, %d marker(s))\n", c.Path, c.Status, c.Markers)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var plainScalar = regexp.MustCompile(`^[A-Za-z0-9/][A-Za-z0-9._/+-]*$`)
+
+// quote renders s as a YAML scalar in the style of the shipped probes: bare
+// when safe, single-quoted when it contains double quotes, double-quoted
+// otherwise.
+func quote(s string) string {
+	switch {
+	case plainScalar.MatchString(s) && !isYAMLKeyword(s):
+		return s
+	case strings.Contains(s, `"`) && !strings.ContainsAny(s, "\n\r\t"):
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+	default:
+		return strconv.Quote(s)
+	}
+}
+
+func isYAMLKeyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		return true
+	}
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
@@ -0,0 +1,127 @@
+package scaffold
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/probe"
+	"github.com/praetorian-inc/julius/pkg/rules"
+	"github.com/praetorian-inc/julius/pkg/scanner"
+)
+
+func exchange(path string, status int, contentType, body string, headers ...string) scanner.Exchange {
+	h := http.Header{}
+	h.Set("Date", "Sat, 17 Oct 2026 10:00:00 GMT")
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		h.Set(headers[i], headers[i+1])
+	}
+	return scanner.Exchange{Method: "GET", Path: path, Status: status, Header: h, Body: []byte(body)}
+}
+
+func TestAnalyze(t *testing.T) {
+	baseline := exchange(BaselinePath, 404, "application/json", `{"detail":"Not Found"}`, "Server", "uvicorn", "X-App-Version", "1.2")
+	observed := []scanner.Exchange{
+		exchange("/api/info", 200, "application/json; charset=utf-8",
+			`{"build_hash":"abc","detail":"x","gpu":{"layers":3},"id":"1","version":"1.2"}`,
+			"Server", "uvicorn", "X-App-Version", "1.2", "X-Build", "abc"),
+		exchange("/", 200, "text/plain", "MyLLM is running\n", "Server", "uvicorn"),
+		exchange("/ui", 200, "text/html", "<html><head><title>\n  MyLLM Console </title></head></html>", "Server", "uvicorn"),
+		exchange("/missing", 404, "application/json", `{"detail":"Not Found"}`),
+		exchange("/spa", 404, "application/json", `{"detail":"Not Found"}`, "Server", "uvicorn"),
+		exchange("/health", 200, "application/json", `{"status":"ok"}`, "Server", "nginx/1.25"),
+	}
+
+	candidates := Analyze(baseline, observed)
+	require.Len(t, candidates, 3, "404s, catch-alls and marker-less responses are dropped")
+
+	assert.Equal(t, Candidate{
+		Path:   "/api/info",
+		Status: 200,
+		Rules: []rules.RawRule{
+			{Type: "status", Value: 200},
+			{Type: "content-type", Value: "application/json"},
+			{Type: "header.contains", Header: "X-Build", Value: ""},
+			{Type: "body.contains", Value: `"build_hash"`},
+			{Type: "body.contains", Value: `"gpu"`},
+			{Type: "body.contains", Value: `"version"`},
+			{Type: "body.contains", Value: `"layers"`},
+			// Server-wide headers, proposed once on the best candidate.
+			{Type: "header.contains", Header: "Server", Value: "uvicorn"},
+			{Type: "header.contains", Header: "X-App-Version", Value: ""},
+		},
+		Markers: 7,
+	}, candidates[0])
+	assert.Equal(t, "/", candidates[1].Path)
+	assert.Contains(t, candidates[1].Rules, rules.RawRule{Type: "body.contains", Value: "MyLLM is running"})
+	assert.Equal(t, "/ui", candidates[2].Path)
+	assert.Contains(t, candidates[2].Rules, rules.RawRule{Type: "body.contains", Value: "MyLLM Console"})
+}
+
+func TestProduct(t *testing.T) {
+	assert.Equal(t, "uvicorn", product("uvicorn"))
+	assert.Equal(t, "Werkzeug", product("Werkzeug/3.0.1 Python/3.11"))
+	assert.Equal(t, "", product(" "))
+}
+
+func TestQuote(t *testing.T) {
+	tests := map[string]string{
+		"/v1/models":       "/v1/models",
+		"application/json": "application/json",
+		`"build_hash"`:     `'"build_hash"'`,
+		`it's "x"`:         `'it''s "x"'`,
+		"":                 `""`,
+		"true":             `"true"`,
+		"1.5":              `"1.5"`,
+		"MyLLM is running": `"MyLLM is running"`,
+		"/api?v=1":         `"/api?v=1"`,
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, quote(in), in)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	candidates := Analyze(
+		exchange(BaselinePath, 404, "", ""),
+		[]scanner.Exchange{
+			exchange("/api/info", 200, "application/json", `{"build_hash":"abc","it's":1}`, "X-Build", "abc"),
+			exchange("/", 200, "text/plain", "MyLLM: ok"),
+			exchange("/version", 200, "application/json", `{"semver":"1.0"}`),
+		},
+	)
+	var b strings.Builder
+	require.NoError(t, Write(&b, Draft{
+		Name:       "my-llm",
+		Target:     "http://localhost:8000",
+		PortHint:   8000,
+		Extends:    "openai-compatible",
+		Matched:    []string{"openai-compatible"},
+		Candidates: candidates,
+		MaxRequest: 2,
+	}))
+	out := b.String()
+	assert.Contains(t, out, "# Already matched by: openai-compatible\n")
+	assert.Contains(t, out, "  #   /version (200, 1 marker(s))\n", "candidates over the limit are listed")
+
+	p, err := probe.ParseProbeStrict([]byte(out))
+	require.NoError(t, err, out)
+	assert.Equal(t, "my-llm", p.Name)
+	assert.Equal(t, "openai-compatible", p.Extends)
+	assert.Equal(t, 8000, p.PortHint)
+	require.Len(t, p.Requests, 2)
+	assert.Equal(t, "/api/info", p.Requests[0].Path)
+	// The status value decodes as uint64; everything else round-trips exactly.
+	assert.EqualValues(t, 200, p.Requests[0].RawMatch[0].Value)
+	assert.Equal(t, candidates[0].Rules[1:], p.Requests[0].RawMatch[1:], "rules survive the YAML round trip")
+	for _, req := range p.Requests {
+		_, err := req.GetRules()
+		assert.NoError(t, err)
+	}
+}
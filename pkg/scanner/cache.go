@@ -1,6 +1,8 @@
 package scanner
 
 import (
+	"bufio"
+	"bytes"
 	"crypto/md5"
 	"encoding/hex"
 	"io"
@@ -36,8 +38,14 @@ func cacheKey(method, url string, headers http.Header, body []byte) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
-func (s *Scanner) cachedRequest(req *http.Request, body []byte) (*http.Response, []byte, error) {
+// cachedRequest sends req once per distinct request and caches the response.
+// With firstEvent set, the body is read only up to the first server-sent
+// event, so a stream the server never closes still returns.
+func (s *Scanner) cachedRequest(req *http.Request, body []byte, firstEvent bool) (*http.Response, []byte, error) {
 	key := cacheKey(req.Method, req.URL.String(), req.Header, body)
+	if firstEvent {
+		key = "sse:" + key
+	}
 
 	// Use singleflight to deduplicate concurrent requests
 	result, err, _ := s.inflight.Do(key, func() (any, error) {
@@ -58,7 +66,13 @@ func (s *Scanner) cachedRequest(req *http.Request, body []byte) (*http.Response,
 			return cached, nil
 		}
 
-		respBody, err := io.ReadAll(io.LimitReader(resp.Body, s.maxResponseSize))
+		limited := io.LimitReader(resp.Body, s.maxResponseSize)
+		var respBody []byte
+		if firstEvent {
+			respBody, err = readFirstEvent(limited)
+		} else {
+			respBody, err = io.ReadAll(limited)
+		}
 		_ = resp.Body.Close()
 		if err != nil {
 			slog.Error("Reading response body", "method", req.Method, "url", req.URL.String(), "err", err)
@@ -89,3 +103,30 @@ func (s *Scanner) cachedRequest(req *http.Request, body []byte) (*http.Response,
 	}
 	return cached.Response, cached.Body, nil
 }
+
+// readFirstEvent reads up to and including the blank line that ends the first
+// server-sent event, or to EOF if the stream ends sooner.
+func readFirstEvent(r io.Reader) ([]byte, error) {
+	var (
+		buf     bytes.Buffer
+		inEvent bool
+	)
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		buf.Write(line)
+		if len(bytes.TrimSpace(line)) == 0 {
+			if inEvent {
+				return buf.Bytes(), nil
+			}
+		} else {
+			inEvent = true
+		}
+		if err == io.EOF {
+			return buf.Bytes(), nil
+		}
+		if err != nil {
+			return buf.Bytes(), err
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/praetorian-inc/julius/pkg/probe"
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// wellKnownResourcePath is the RFC 9728 protected-resource metadata document
+// at the host root. Its resource field is the URL of the protected endpoint.
+const wellKnownResourcePath = "/.well-known/oauth-protected-resource"
+
+// withMCPDiscovery returns probes followed by copies of every probe with an
+// mcp section rooted at each of its discovery paths and at the endpoint path
+// advertised by the target's well-known metadata, so a bare host finds an MCP
+// server one path segment down.
+func (s *Scanner) withMCPDiscovery(target string, probes []*types.Probe) []*types.Probe {
+	if !s.mcpDiscover {
+		return probes
+	}
+
+	var (
+		expanded  = slices.Clone(probes)
+		wellKnown []string
+		fetched   bool
+	)
+	for _, p := range probes {
+		if p.MCP == nil {
+			continue
+		}
+		if !fetched {
+			wellKnown = s.wellKnownMCPPaths(target)
+			fetched = true
+		}
+
+		var paths []string
+		for _, path := range append(slices.Clone(p.MCP.DiscoveryPaths), wellKnown...) {
+			path = "/" + strings.Trim(path, "/")
+			if path != "/" && !slices.Contains(paths, path) {
+				paths = append(paths, path)
+			}
+		}
+		expanded = append(expanded, probe.ExpandWithBasePaths([]*types.Probe{p}, paths)[1:]...)
+	}
+	return expanded
+}
+
+// wellKnownMCPPaths reads the endpoint path from the target host's RFC 9728
+// metadata, relative to the target. Resources on other hosts, or outside the
+// target's own path, are ignored.
+func (s *Scanner) wellKnownMCPPaths(target string) []string {
+	base, err := url.Parse(target)
+	if err != nil {
+		return nil
+	}
+	root := base.Scheme + "://" + base.Host
+
+	resp, body, err := s.doHTTPRequest(root, http.MethodGet, wellKnownResourcePath, "", nil)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	var doc struct {
+		Resource string `json:"resource"`
+	}
+	if json.Unmarshal(body, &doc) != nil || doc.Resource == "" {
+		return nil
+	}
+	resource, err := url.Parse(doc.Resource)
+	if err != nil || !strings.EqualFold(resource.Host, base.Host) {
+		return nil
+	}
+
+	rel, ok := strings.CutPrefix(resource.Path, strings.TrimRight(base.Path, "/"))
+	if !ok || strings.Trim(rel, "/") == "" {
+		return nil
+	}
+	return []string{rel}
+}
@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/probe"
+	"github.com/praetorian-inc/julius/pkg/types"
+	"github.com/praetorian-inc/julius/probes"
+)
+
+func loadEmbeddedProbes(t *testing.T, names ...string) []*types.Probe {
+	t.Helper()
+	loaded, err := probe.LoadProbesFromFS(probes.EmbeddedProbes, ".")
+	require.NoError(t, err)
+	var selected []*types.Probe
+	for _, p := range loaded {
+		for _, name := range names {
+			if p.Name == name {
+				selected = append(selected, p)
+			}
+		}
+	}
+	require.Len(t, selected, len(names))
+	return selected
+}
+
+func TestScan_MCPDiscovery(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/mcp":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2025-06-18","capabilities":{},"serverInfo":{"name":"demo","version":"1"}}}`))
+		case "/sse":
+			// The legacy transport never closes the stream.
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("event: endpoint\ndata: /messages?session_id=abc\n\n"))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	loaded := loadEmbeddedProbes(t, "mcp-server", "mcp-server-sse")
+
+	s := NewScanner(WithTimeout(5 * time.Second))
+	assert.Empty(t, s.Scan(server.URL, loaded, false), "without discovery only the exact URL is probed")
+
+	start := time.Now()
+	s = NewScanner(WithTimeout(5*time.Second), WithMCPDiscovery())
+	results := s.Scan(server.URL, loaded, false)
+	assert.Less(t, time.Since(start), 4*time.Second, "an open event stream must not hold the scan until timeout")
+
+	matched := map[string]string{}
+	for _, r := range results {
+		matched[r.Service] = r.Target
+	}
+	assert.Equal(t, server.URL+"/api/mcp", matched["mcp-server"])
+	assert.Equal(t, server.URL+"/sse", matched["mcp-server-sse"])
+}
+
+func TestWellKnownMCPPaths(t *testing.T) {
+	var resource string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != wellKnownResourcePath {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"resource": resource})
+	}))
+	defer server.Close()
+
+	s := NewScanner(WithTimeout(5 * time.Second))
+
+	resource = server.URL + "/tools/custom-mcp"
+	assert.Equal(t, []string{"/tools/custom-mcp"}, s.wellKnownMCPPaths(server.URL))
+	assert.Equal(t, []string{"/custom-mcp"}, s.wellKnownMCPPaths(server.URL+"/tools"), "relative to the target path")
+	assert.Empty(t, s.wellKnownMCPPaths(server.URL+"/other"), "resource outside the target path")
+
+	resource = strings.Replace(server.URL, "127.0.0.1", "localhost", 1) + "/mcp"
+	s = NewScanner(WithTimeout(5 * time.Second)) // fresh response cache
+	assert.Empty(t, s.wellKnownMCPPaths(server.URL), "resource on another host")
+}
+
+func TestReadFirstEvent(t *testing.T) {
+	got, err := readFirstEvent(strings.NewReader("\nevent: endpoint\ndata: /messages\n\nevent: ping\n\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "\nevent: endpoint\ndata: /messages\n\n", string(got))
+
+	got, err = readFirstEvent(strings.NewReader("data: partial"))
+	require.NoError(t, err)
+	assert.Equal(t, "data: partial", string(got), "EOF ends the event")
+}
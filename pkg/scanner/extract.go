@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/itchyny/gojq"
+
+	"github.com/praetorian-inc/julius/pkg/types"
 )
 
 func NormalizeTarget(target string) string {
@@ -105,3 +108,80 @@ func extractModels(body []byte, jqExpr string) ([]string, error) {
 
 	return models, nil
 }
+
+// extractModelDetails selects one value per model with the items expression
+// and evaluates every fields expression against it. A field expression that
+// yields nothing or null is omitted; one that yields several values keeps the
+// first.
+func extractModelDetails(body []byte, items string, fields map[string]string) ([]types.ModelDetail, error) {
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	itemsQuery, err := gojq.Parse(items)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jq expression for items: %w", err)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fieldQueries := make(map[string]*gojq.Query, len(fields))
+	for _, k := range keys {
+		q, err := gojq.Parse(fields[k])
+		if err != nil {
+			return nil, fmt.Errorf("invalid jq expression for field %s: %w", k, err)
+		}
+		fieldQueries[k] = q
+	}
+
+	details := []types.ModelDetail{}
+	iter := itemsQuery.Run(data)
+	for {
+		item, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, isErr := item.(error); isErr {
+			return nil, fmt.Errorf("jq execution error for items: %w", err)
+		}
+
+		var detail types.ModelDetail
+		for _, k := range keys {
+			v, err := firstValue(fieldQueries[k], item)
+			if err != nil {
+				return nil, fmt.Errorf("jq execution error for field %s: %w", k, err)
+			}
+			if v == nil {
+				continue
+			}
+			if name, ok := v.(string); ok && k == "name" {
+				detail.Name = name
+				continue
+			}
+			if detail.Fields == nil {
+				detail.Fields = make(map[string]any)
+			}
+			detail.Fields[k] = v
+		}
+		details = append(details, detail)
+	}
+
+	return details, nil
+}
+
+func firstValue(query *gojq.Query, input any) (any, error) {
+	iter := query.Run(input)
+	v, ok := iter.Next()
+	if !ok {
+		return nil, nil
+	}
+	if err, isErr := v.(error); isErr {
+		return nil, err
+	}
+	return v, nil
+}
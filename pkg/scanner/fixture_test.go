@@ -1,6 +1,7 @@
 // pkg/scanner/fixture_test.go
 //
-// Golden-file HTTP fixture harness for Julius probes.
+// Golden-file HTTP fixture harness for Julius probes. The fixture format lives
+// in pkg/fixture and is shared with the `julius test` command.
 //
 // Each probe in probes/*.yaml has a companion fixture in
 // pkg/scanner/testdata/fixtures/<probe>.yaml describing one or more cases. A
@@ -22,19 +23,13 @@
 package scanner
 
 import (
-	"io"
-	"net/http"
-	"net/http/httptest"
-	"os"
-	"path/filepath"
-	"strings"
 	"testing"
 	"time"
 
-	"github.com/goccy/go-yaml"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/praetorian-inc/julius/pkg/fixture"
 	"github.com/praetorian-inc/julius/pkg/probe"
 	"github.com/praetorian-inc/julius/pkg/types"
 	"github.com/praetorian-inc/julius/probes"
@@ -42,64 +37,6 @@ import (
 
 const fixturesDir = "testdata/fixtures"
 
-// fixture is the golden file for a single probe: probes/<probe>.yaml is
-// validated against the cases here.
-type fixture struct {
-	Probe string        `yaml:"probe"`
-	Cases []fixtureCase `yaml:"cases"`
-	path  string        // source file, for error messages
-}
-
-// fixtureCase is one scenario: a set of canned responses and the match result
-// the probe must produce against them.
-type fixtureCase struct {
-	Name      string                     `yaml:"name"`
-	Match     bool                       `yaml:"match"`
-	Responses map[string]fixtureResponse `yaml:"responses"`
-}
-
-// fixtureResponse is the canned HTTP response served for a given request path.
-// Method, when set, restricts the response to requests using that HTTP method;
-// a mismatch is served 405 so a probe whose method regresses (e.g. POST->GET)
-// no longer receives the canned body. Empty means any method is accepted.
-type fixtureResponse struct {
-	Status  int               `yaml:"status"`
-	Method  string            `yaml:"method,omitempty"`
-	Headers map[string]string `yaml:"headers,omitempty"`
-	Body    string            `yaml:"body,omitempty"`
-}
-
-// handler serves the case's canned responses keyed by the full request URI
-// (path plus ?query), falling back to the bare path if no exact match is
-// found. Any path not present in the fixture returns 404 with an empty body,
-// which lets negative cases (and unmet require:all chains) fail naturally.
-func (c fixtureCase) handler() http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		key := r.URL.RequestURI() // path plus ?query, matching how probes specify paths
-		resp, ok := c.Responses[key]
-		if !ok {
-			resp, ok = c.Responses[r.URL.Path]
-		}
-		if !ok {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-		if resp.Method != "" && !strings.EqualFold(r.Method, resp.Method) {
-			w.WriteHeader(http.StatusMethodNotAllowed)
-			return
-		}
-		for k, v := range resp.Headers {
-			w.Header().Set(k, v)
-		}
-		status := resp.Status
-		if status == 0 {
-			status = http.StatusOK
-		}
-		w.WriteHeader(status)
-		_, _ = io.WriteString(w, resp.Body)
-	})
-}
-
 // loadProbeMap loads every embedded probe, keyed by name. This is exactly the
 // probe set that ships in the binary.
 func loadProbeMap(t *testing.T) map[string]*types.Probe {
@@ -117,25 +54,10 @@ func loadProbeMap(t *testing.T) map[string]*types.Probe {
 }
 
 // loadFixtures reads and parses every fixture YAML in the fixtures directory.
-func loadFixtures(t *testing.T) []fixture {
+func loadFixtures(t *testing.T) []fixture.Fixture {
 	t.Helper()
-	entries, err := os.ReadDir(fixturesDir)
-	require.NoError(t, err, "reading fixtures directory")
-
-	var fixtures []fixture
-	for _, e := range entries {
-		if e.IsDir() || !strings.HasSuffix(e.Name(), ".yaml") {
-			continue
-		}
-		path := filepath.Join(fixturesDir, e.Name())
-		data, err := os.ReadFile(path)
-		require.NoError(t, err, "reading %s", path)
-
-		var fx fixture
-		require.NoError(t, yaml.Unmarshal(data, &fx), "parsing %s", path)
-		fx.path = path
-		fixtures = append(fixtures, fx)
-	}
+	fixtures, err := fixture.Load(fixturesDir)
+	require.NoError(t, err, "loading fixtures")
 	require.NotEmpty(t, fixtures, "no fixtures found in %s", fixturesDir)
 	return fixtures
 }
@@ -148,17 +70,17 @@ func TestProbeFixtures(t *testing.T) {
 
 	for _, fx := range loadFixtures(t) {
 		p, ok := probeMap[fx.Probe]
-		if !assert.Truef(t, ok, "%s references unknown probe %q", fx.path, fx.Probe) {
+		if !assert.Truef(t, ok, "%s references unknown probe %q", fx.Path, fx.Probe) {
 			continue
 		}
-		if !assert.NotEmptyf(t, fx.Cases, "%s has no cases", fx.path) {
+		if !assert.NotEmptyf(t, fx.Cases, "%s has no cases", fx.Path) {
 			continue
 		}
 
 		t.Run(fx.Probe, func(t *testing.T) {
 			t.Parallel()
 			for _, c := range fx.Cases {
-				if !assert.NotEmptyf(t, c.Name, "%s has a case with no name", fx.path) {
+				if !assert.NotEmptyf(t, c.Name, "%s has a case with no name", fx.Path) {
 					continue
 				}
 				if !assert.NotEmptyf(t, c.Responses, "%s/%s has no responses", fx.Probe, c.Name) {
@@ -167,16 +89,11 @@ func TestProbeFixtures(t *testing.T) {
 
 				t.Run(c.Name, func(t *testing.T) {
 					t.Parallel()
-					server := httptest.NewServer(c.handler())
-					defer server.Close()
-
-					s := NewScanner(WithTimeout(5 * time.Second))
-					results := s.Scan(server.URL, []*types.Probe{p}, false)
-					matched := len(results) > 0
-
-					assert.Equalf(t, c.Match, matched,
-						"probe %q case %q: expected match=%v, got match=%v",
-						fx.Probe, c.Name, c.Match, matched)
+					err := c.Run(func(target string) []types.Result {
+						s := NewScanner(WithTimeout(5 * time.Second))
+						return s.Scan(target, []*types.Probe{p}, false)
+					})
+					assert.NoErrorf(t, err, "probe %q case %q", fx.Probe, c.Name)
 				})
 			}
 		})
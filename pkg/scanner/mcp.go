@@ -0,0 +1,336 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/praetorian-inc/julius/pkg/credentials"
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+const (
+	mcpSessionHeader  = "Mcp-Session-Id"
+	mcpProtocolHeader = "MCP-Protocol-Version"
+	// mcpMaxPages bounds nextCursor pagination of each list method.
+	mcpMaxPages = 10
+)
+
+// mcpSession is one Streamable HTTP session with an MCP server. Requests are
+// sent straight through the client, not the response cache: the session ID
+// and message IDs make every exchange unique.
+type mcpSession struct {
+	s               *Scanner
+	endpoint        string
+	headers         map[string]string
+	protocolVersion string
+	sessionID       string
+	nextID          int
+}
+
+type rpcMessage struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// enumerateMCP completes an MCP session against endpoint and lists the
+// server's tools, resources and prompts. Listing failures are recorded in
+// the inventory's Error rather than discarding what was already listed.
+func (s *Scanner) enumerateMCP(endpoint string, cfg *types.MCPConfig, cred *credentials.Credential) *types.MCPInventory {
+	if cfg.GetTransport() != types.MCPTransportStreamableHTTP {
+		return &types.MCPInventory{Error: "enumeration is only supported over the Streamable HTTP transport"}
+	}
+
+	sess := &mcpSession{
+		s:               s,
+		endpoint:        endpoint,
+		headers:         make(map[string]string),
+		protocolVersion: cfg.GetProtocolVersion(),
+		nextID:          1,
+	}
+	for k, v := range s.headers {
+		sess.headers[k] = v
+	}
+	if cred != nil {
+		for k, v := range cred.AuthHeaders() {
+			sess.headers[k] = v
+		}
+	}
+
+	inv := &types.MCPInventory{}
+
+	var init struct {
+		ProtocolVersion string                     `json:"protocolVersion"`
+		Capabilities    map[string]json.RawMessage `json:"capabilities"`
+		ServerInfo      struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"serverInfo"`
+	}
+	err := sess.call("initialize", map[string]any{
+		"protocolVersion": sess.protocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      map[string]string{"name": "julius", "version": "1.0"},
+	}, &init)
+	if err != nil {
+		inv.Error = "initialize: " + err.Error()
+		return inv
+	}
+	defer sess.close()
+
+	if init.ProtocolVersion != "" {
+		sess.protocolVersion = init.ProtocolVersion
+	}
+	inv.ProtocolVersion = sess.protocolVersion
+	inv.ServerName = init.ServerInfo.Name
+	inv.ServerVersion = init.ServerInfo.Version
+
+	if err := sess.notify("notifications/initialized"); err != nil {
+		inv.Error = "initialized: " + err.Error()
+		return inv
+	}
+
+	// A server that declares capabilities only serves the lists it declares;
+	// one that declares none is asked for everything.
+	offers := func(capability string) bool {
+		if init.Capabilities == nil {
+			return true
+		}
+		_, ok := init.Capabilities[capability]
+		return ok
+	}
+
+	var errs []string
+	if offers("tools") {
+		if inv.Tools, err = mcpList[types.MCPTool](sess, "tools/list", "tools"); err != nil {
+			errs = append(errs, "tools/list: "+err.Error())
+		}
+	}
+	if offers("resources") {
+		if inv.Resources, err = mcpList[types.MCPResource](sess, "resources/list", "resources"); err != nil {
+			errs = append(errs, "resources/list: "+err.Error())
+		}
+	}
+	if offers("prompts") {
+		if inv.Prompts, err = mcpList[types.MCPPrompt](sess, "prompts/list", "prompts"); err != nil {
+			errs = append(errs, "prompts/list: "+err.Error())
+		}
+	}
+	inv.Error = strings.Join(errs, "; ")
+	return inv
+}
+
+// mcpList calls a paginated list method and collects the items under field,
+// following nextCursor up to mcpMaxPages pages.
+func mcpList[T any](sess *mcpSession, method, field string) ([]T, error) {
+	var (
+		all    []T
+		cursor string
+	)
+	for range mcpMaxPages {
+		var params any
+		if cursor != "" {
+			params = map[string]string{"cursor": cursor}
+		}
+
+		var page map[string]json.RawMessage
+		if err := sess.call(method, params, &page); err != nil {
+			return all, err
+		}
+		if raw, ok := page[field]; ok {
+			var items []T
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return all, fmt.Errorf("decoding %s: %w", field, err)
+			}
+			all = append(all, items...)
+		}
+
+		var next string
+		if raw, ok := page["nextCursor"]; ok {
+			_ = json.Unmarshal(raw, &next)
+		}
+		if next == "" || next == cursor {
+			break
+		}
+		cursor = next
+	}
+	return all, nil
+}
+
+// call sends a JSON-RPC request and decodes the matching response's result
+// into out.
+func (m *mcpSession) call(method string, params any, out any) error {
+	id := m.nextID
+	m.nextID++
+
+	msg := map[string]any{"jsonrpc": "2.0", "id": id, "method": method}
+	if params != nil {
+		msg["params"] = params
+	}
+
+	resp, err := m.post(msg)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned %d", resp.StatusCode)
+	}
+	if method == "initialize" {
+		m.sessionID = resp.Header.Get(mcpSessionHeader)
+	}
+
+	reply, err := readRPCResponse(resp, strconv.Itoa(id), m.s.maxResponseSize)
+	if err != nil {
+		return err
+	}
+	if reply.Error != nil {
+		return fmt.Errorf("%s (code %d)", reply.Error.Message, reply.Error.Code)
+	}
+	if err := json.Unmarshal(reply.Result, out); err != nil {
+		return fmt.Errorf("decoding result: %w", err)
+	}
+	return nil
+}
+
+// notify sends a JSON-RPC notification, which the server acknowledges with
+// 202 Accepted and no body.
+func (m *mcpSession) notify(method string) error {
+	resp, err := m.post(map[string]any{"jsonrpc": "2.0", "method": method})
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("server returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (m *mcpSession) post(msg map[string]any) (*http.Response, error) {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodPost, m.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	m.setSessionHeaders(req)
+	return m.s.client.Do(req)
+}
+
+// close ends the session. Servers that don't support explicit termination
+// answer 405, which is fine.
+func (m *mcpSession) close() {
+	if m.sessionID == "" {
+		return
+	}
+	req, err := http.NewRequest(http.MethodDelete, m.endpoint, nil)
+	if err != nil {
+		return
+	}
+	m.setSessionHeaders(req)
+	if resp, err := m.s.client.Do(req); err == nil {
+		_ = resp.Body.Close()
+	}
+}
+
+func (m *mcpSession) setSessionHeaders(req *http.Request) {
+	for k, v := range m.headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set(mcpProtocolHeader, m.protocolVersion)
+	if m.sessionID != "" {
+		req.Header.Set(mcpSessionHeader, m.sessionID)
+	}
+}
+
+// readRPCResponse returns the JSON-RPC response with the given id from either
+// framing a Streamable HTTP server may use: a plain JSON body (a single
+// message or a batch) or an SSE stream of `data:` events. The SSE stream is
+// read only until the response arrives, since servers may hold it open.
+func readRPCResponse(resp *http.Response, id string, limit int64) (*rpcMessage, error) {
+	body := io.LimitReader(resp.Body, limit)
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/event-stream" {
+		var data []string
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 64*1024), int(min(limit, 1<<30)))
+		for scanner.Scan() {
+			line := scanner.Text()
+			if value, ok := strings.CutPrefix(line, "data:"); ok {
+				data = append(data, strings.TrimPrefix(value, " "))
+				continue
+			}
+			if line != "" {
+				continue
+			}
+			if msg := findRPCResponse([]byte(strings.Join(data, "\n")), id); msg != nil {
+				return msg, nil
+			}
+			data = nil
+		}
+		if msg := findRPCResponse([]byte(strings.Join(data, "\n")), id); msg != nil {
+			return msg, nil
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading event stream: %w", err)
+		}
+		return nil, fmt.Errorf("no response with id %s in event stream", id)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if msg := findRPCResponse(data, id); msg != nil {
+		return msg, nil
+	}
+	return nil, fmt.Errorf("no response with id %s", id)
+}
+
+// findRPCResponse decodes a JSON-RPC message or batch and returns the
+// response carrying id, or nil.
+func findRPCResponse(data []byte, id string) *rpcMessage {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil
+	}
+
+	var batch []rpcMessage
+	if data[0] == '[' {
+		if json.Unmarshal(data, &batch) != nil {
+			return nil
+		}
+	} else {
+		var msg rpcMessage
+		if json.Unmarshal(data, &msg) != nil {
+			return nil
+		}
+		batch = []rpcMessage{msg}
+	}
+
+	for i := range batch {
+		if string(bytes.TrimSpace(batch[i].ID)) == id {
+			return &batch[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/rules"
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// fakeMCPServer is a minimal Streamable HTTP MCP server. initialize replies
+// with plain JSON; list methods reply over SSE, and tools/list is split over
+// two pages.
+type fakeMCPServer struct {
+	mu      sync.Mutex
+	methods []string
+	deleted bool
+	caps    map[string]any
+}
+
+func (f *fakeMCPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodDelete {
+		f.mu.Lock()
+		f.deleted = r.Header.Get(mcpSessionHeader) == "sess-1"
+		f.mu.Unlock()
+		return
+	}
+
+	var msg struct {
+		ID     *int           `json:"id"`
+		Method string         `json:"method"`
+		Params map[string]any `json:"params"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&msg)
+
+	f.mu.Lock()
+	f.methods = append(f.methods, msg.Method)
+	f.mu.Unlock()
+
+	if msg.Method != "initialize" && r.Header.Get(mcpSessionHeader) != "sess-1" {
+		http.Error(w, "missing session", http.StatusBadRequest)
+		return
+	}
+	if msg.ID == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var result any
+	switch msg.Method {
+	case "initialize":
+		w.Header().Set(mcpSessionHeader, "sess-1")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": *msg.ID, "result": map[string]any{
+			"protocolVersion": "2025-06-18",
+			"capabilities":    f.caps,
+			"serverInfo":      map[string]string{"name": "fake", "version": "0.1"},
+		}})
+		return
+	case "tools/list":
+		if msg.Params["cursor"] == "p2" {
+			result = map[string]any{"tools": []any{map[string]any{"name": "exec", "description": "Run a shell command", "inputSchema": map[string]any{"type": "object"}}}}
+		} else {
+			result = map[string]any{"tools": []any{map[string]any{"name": "read_file"}}, "nextCursor": "p2"}
+		}
+	case "resources/list":
+		result = map[string]any{"resources": []any{map[string]any{"uri": "file:///etc/passwd", "name": "passwd", "mimeType": "text/plain"}}}
+	case "prompts/list":
+		result = map[string]any{"prompts": []any{map[string]any{"name": "summarize", "arguments": []any{map[string]any{"name": "text", "required": true}}}}}
+	}
+
+	data, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": *msg.ID, "result": result})
+	w.Header().Set("Content-Type", "text/event-stream")
+	_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+	w.(http.Flusher).Flush()
+	// Hold the stream open the way some servers do; the client must not
+	// wait for EOF once its response has arrived.
+	<-r.Context().Done()
+}
+
+func mcpTestProbe() *types.Probe {
+	return &types.Probe{
+		Name: "mcp-server",
+		Requests: []types.Request{{
+			Method:   "POST",
+			Body:     `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+			Auth:     types.AuthOpen,
+			RawMatch: []rules.RawRule{{Type: "status", Value: 200}},
+		}},
+		MCP: &types.MCPConfig{},
+	}
+}
+
+func TestScan_MCPEnumeration(t *testing.T) {
+	fake := &fakeMCPServer{caps: map[string]any{"tools": map[string]any{}, "resources": map[string]any{}, "prompts": map[string]any{}}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	s := NewScanner(WithTimeout(5*time.Second), WithMCPEnumeration())
+	results := s.Scan(server.URL, []*types.Probe{mcpTestProbe()}, false)
+
+	require.Len(t, results, 1)
+	inv := results[0].MCP
+	require.NotNil(t, inv)
+	assert.Empty(t, inv.Error)
+	assert.Equal(t, "2025-06-18", inv.ProtocolVersion)
+	assert.Equal(t, "fake", inv.ServerName)
+	assert.Equal(t, "0.1", inv.ServerVersion)
+	assert.Equal(t, []types.MCPTool{
+		{Name: "read_file"},
+		{Name: "exec", Description: "Run a shell command", InputSchema: map[string]any{"type": "object"}},
+	}, inv.Tools)
+	assert.Equal(t, []types.MCPResource{{URI: "file:///etc/passwd", Name: "passwd", MimeType: "text/plain"}}, inv.Resources)
+	assert.Equal(t, []types.MCPPrompt{{Name: "summarize", Arguments: []types.MCPPromptArgument{{Name: "text", Required: true}}}}, inv.Prompts)
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.Contains(t, fake.methods, "notifications/initialized")
+	assert.True(t, fake.deleted, "the session is terminated when enumeration finishes")
+}
+
+func TestScan_MCPEnumeration_OnlyDeclaredCapabilities(t *testing.T) {
+	fake := &fakeMCPServer{caps: map[string]any{"tools": map[string]any{}}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	results := NewScanner(WithTimeout(5*time.Second), WithMCPEnumeration()).Scan(server.URL, []*types.Probe{mcpTestProbe()}, false)
+
+	require.Len(t, results, 1)
+	require.NotNil(t, results[0].MCP)
+	assert.Len(t, results[0].MCP.Tools, 2)
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	assert.NotContains(t, fake.methods, "resources/list")
+	assert.NotContains(t, fake.methods, "prompts/list")
+}
+
+func TestScan_MCPEnumeration_SkipsAuthRequired(t *testing.T) {
+	fake := &fakeMCPServer{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	p := mcpTestProbe()
+	p.Requests[0].Auth = types.AuthRequired
+	results := NewScanner(WithTimeout(5*time.Second), WithMCPEnumeration()).Scan(server.URL, []*types.Probe{p}, false)
+
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].MCP)
+}
+
+func TestFindRPCResponse(t *testing.T) {
+	batch := []byte(`[{"jsonrpc":"2.0","method":"notifications/progress"},{"jsonrpc":"2.0","id":2,"result":{"ok":true}}]`)
+	msg := findRPCResponse(batch, "2")
+	require.NotNil(t, msg)
+	assert.JSONEq(t, `{"ok":true}`, string(msg.Result))
+
+	assert.Nil(t, findRPCResponse(batch, "3"))
+	assert.Nil(t, findRPCResponse([]byte("not json"), "1"))
+
+	msg = findRPCResponse([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"Method not found"}}`), "1")
+	require.NotNil(t, msg)
+	assert.Equal(t, -32601, msg.Error.Code)
+}
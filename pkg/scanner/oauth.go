@@ -0,0 +1,233 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// resourceMetadataParam extracts the RFC 9728 resource_metadata parameter
+// from a WWW-Authenticate challenge, quoted or not.
+var resourceMetadataParam = regexp.MustCompile(`(?i)\bresource_metadata\s*=\s*(?:"([^"]*)"|([^\s,]+))`)
+
+// maxAuthorizationServers caps how many issuers listed in protected-resource
+// metadata are fetched, so a target cannot fan discovery out without bound.
+const maxAuthorizationServers = 4
+
+// discoverOAuth follows the resource_metadata link of an auth-gated match to
+// the RFC 9728 protected-resource metadata, then fetches the RFC 8414
+// metadata of the authorization servers it lists. The target chooses these
+// URLs, so links to other hosts are only followed with WithOAuthExternal;
+// otherwise off-host authorization servers are listed but not fetched. All
+// requests are sent without -H headers or credentials.
+func (s *Scanner) discoverOAuth(target string, req types.Request) *types.OAuthMetadata {
+	resourceURL := target + req.Path
+
+	// The matched response is cached, so this costs no request.
+	resp, _, err := s.doProbeRequest(target, req)
+	if err != nil {
+		return &types.OAuthMetadata{Error: err.Error()}
+	}
+
+	metadataURL, err := resourceMetadataURL(resourceURL, resp.Header.Values("WWW-Authenticate"))
+	if err != nil {
+		return &types.OAuthMetadata{Error: err.Error()}
+	}
+	om := &types.OAuthMetadata{ResourceMetadataURL: metadataURL}
+	resourceHost := hostname(resourceURL)
+	if !s.oauthExternal && hostname(metadataURL) != resourceHost {
+		om.Error = "protected resource metadata is on another host; not followed"
+		return om
+	}
+
+	var prm struct {
+		Resource               string   `json:"resource"`
+		ResourceName           string   `json:"resource_name"`
+		AuthorizationServers   []string `json:"authorization_servers"`
+		ScopesSupported        []string `json:"scopes_supported"`
+		BearerMethodsSupported []string `json:"bearer_methods_supported"`
+	}
+	if err := s.fetchJSON(metadataURL, &prm); err != nil {
+		om.Error = "protected resource metadata: " + err.Error()
+		return om
+	}
+	om.Resource = prm.Resource
+	om.ResourceName = prm.ResourceName
+	om.ScopesSupported = prm.ScopesSupported
+	om.BearerMethodsSupported = prm.BearerMethodsSupported
+
+	switch {
+	case prm.Resource == "":
+		om.Warnings = append(om.Warnings, "protected resource metadata has no resource identifier")
+	case !sameURL(prm.Resource, resourceURL):
+		om.Warnings = append(om.Warnings, fmt.Sprintf("resource %q does not match the scanned URL %q", prm.Resource, resourceURL))
+	}
+	if len(prm.AuthorizationServers) == 0 {
+		om.Warnings = append(om.Warnings, "protected resource metadata lists no authorization servers")
+	}
+
+	issuers := prm.AuthorizationServers
+	if len(issuers) > maxAuthorizationServers {
+		om.Warnings = append(om.Warnings, fmt.Sprintf("protected resource metadata lists %d authorization servers; only the first %d were fetched", len(issuers), maxAuthorizationServers))
+		issuers = issuers[:maxAuthorizationServers]
+	}
+	for _, issuer := range issuers {
+		as, warnings := s.fetchAuthorizationServer(issuer, resourceHost)
+		om.AuthorizationServers = append(om.AuthorizationServers, as)
+		om.Warnings = append(om.Warnings, warnings...)
+	}
+	return om
+}
+
+// fetchAuthorizationServer fetches RFC 8414 metadata for issuer, falling back
+// to OpenID Connect discovery, which many providers serve instead.
+func (s *Scanner) fetchAuthorizationServer(issuer, resourceHost string) (types.OAuthAuthorizationServer, []string) {
+	as := types.OAuthAuthorizationServer{
+		Issuer:     issuer,
+		ThirdParty: hostname(issuer) != resourceHost,
+	}
+
+	var warnings []string
+	if strings.HasPrefix(strings.ToLower(issuer), "http://") {
+		warnings = append(warnings, fmt.Sprintf("authorization server %s uses plain HTTP", issuer))
+	}
+
+	if as.ThirdParty && !s.oauthExternal {
+		as.Error = "authorization server is on another host; not fetched"
+		return as, warnings
+	}
+
+	var lastErr error
+	for _, metadataURL := range authorizationServerMetadataURLs(issuer) {
+		var md struct {
+			Issuer                        string   `json:"issuer"`
+			AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+			TokenEndpoint                 string   `json:"token_endpoint"`
+			RegistrationEndpoint          string   `json:"registration_endpoint"`
+			ScopesSupported               []string `json:"scopes_supported"`
+			GrantTypesSupported           []string `json:"grant_types_supported"`
+			CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported"`
+		}
+		if err := s.fetchJSON(metadataURL, &md); err != nil {
+			lastErr = err
+			continue
+		}
+
+		as.MetadataURL = metadataURL
+		as.AuthorizationEndpoint = md.AuthorizationEndpoint
+		as.TokenEndpoint = md.TokenEndpoint
+		as.RegistrationEndpoint = md.RegistrationEndpoint
+		as.ScopesSupported = md.ScopesSupported
+		as.GrantTypesSupported = md.GrantTypesSupported
+		as.CodeChallengeMethodsSupported = md.CodeChallengeMethodsSupported
+
+		if !sameURL(md.Issuer, issuer) {
+			warnings = append(warnings, fmt.Sprintf("authorization server %s reports issuer %q", issuer, md.Issuer))
+		}
+		if len(md.CodeChallengeMethodsSupported) > 0 && !slices.Contains(md.CodeChallengeMethodsSupported, "S256") {
+			warnings = append(warnings, fmt.Sprintf("authorization server %s does not support PKCE S256", issuer))
+		}
+		return as, warnings
+	}
+
+	if lastErr != nil {
+		as.Error = lastErr.Error()
+	}
+	return as, warnings
+}
+
+// resourceMetadataURL returns the metadata URL advertised in the challenge,
+// resolved against the resource URL, or the RFC 9728 well-known location when
+// no challenge carries one.
+func resourceMetadataURL(resourceURL string, challenges []string) (string, error) {
+	base, err := url.Parse(resourceURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing resource URL: %w", err)
+	}
+
+	for _, challenge := range challenges {
+		m := resourceMetadataParam.FindStringSubmatch(challenge)
+		if m == nil {
+			continue
+		}
+		ref := m[1]
+		if ref == "" {
+			ref = m[2]
+		}
+		u, err := base.Parse(ref)
+		if err != nil {
+			return "", fmt.Errorf("parsing resource_metadata %q: %w", ref, err)
+		}
+		return u.String(), nil
+	}
+
+	return insertWellKnown(base, "oauth-protected-resource"), nil
+}
+
+// authorizationServerMetadataURLs lists the discovery locations for issuer in
+// the order MCP clients try them: RFC 8414 with path insertion, OpenID
+// Connect with path insertion, then OpenID Connect with path appending.
+func authorizationServerMetadataURLs(issuer string) []string {
+	u, err := url.Parse(issuer)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	urls := []string{
+		insertWellKnown(u, "oauth-authorization-server"),
+		insertWellKnown(u, "openid-configuration"),
+	}
+	appended := strings.TrimRight(issuer, "/") + "/.well-known/openid-configuration"
+	if !slices.Contains(urls, appended) {
+		urls = append(urls, appended)
+	}
+	return urls
+}
+
+// insertWellKnown builds <scheme>://<host>/.well-known/<name><path>, the
+// path-insertion form shared by RFC 8414 and RFC 9728.
+func insertWellKnown(u *url.URL, name string) string {
+	return u.Scheme + "://" + u.Host + "/.well-known/" + name + strings.TrimRight(u.EscapedPath(), "/")
+}
+
+// fetchJSON GETs url without the scanner's global headers and decodes a 200
+// JSON response into out.
+func (s *Scanner) fetchJSON(url string, out any) error {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, s.maxResponseSize)).Decode(out); err != nil {
+		return fmt.Errorf("decoding %s: %w", url, err)
+	}
+	return nil
+}
+
+func sameURL(a, b string) bool {
+	return strings.TrimRight(a, "/") == strings.TrimRight(b, "/")
+}
+
+func hostname(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
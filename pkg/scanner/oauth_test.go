@@ -0,0 +1,174 @@
+package scanner
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/rules"
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+func TestScan_OAuthDiscovery(t *testing.T) {
+	// The authorization server only serves OpenID discovery, so the RFC 8414
+	// location is tried first and falls through.
+	authServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/openid-configuration/tenant" {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Empty(t, r.Header.Get("X-Scan-Token"), "-H headers must not leave the target")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                           "https://login.example.com/tenant",
+			"authorization_endpoint":           "https://login.example.com/authorize",
+			"token_endpoint":                   "https://login.example.com/token",
+			"code_challenge_methods_supported": []string{"plain"},
+		})
+	}))
+	defer authServer.Close()
+	// Different hostname, same listener: the authorization server is "third party".
+	issuer := strings.Replace(authServer.URL, "127.0.0.1", "localhost", 1) + "/tenant"
+
+	var resourceURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/mcp":
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token", resource_metadata="/.well-known/oauth-protected-resource/mcp"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/.well-known/oauth-protected-resource/mcp":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"resource":              resourceURL,
+				"authorization_servers": []string{issuer},
+				"scopes_supported":      []string{"mcp:read", "mcp:write"},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	resourceURL = server.URL + "/mcp"
+
+	p := &types.Probe{
+		Name: "mcp-server",
+		Requests: []types.Request{{
+			Path:     "/mcp",
+			Method:   "POST",
+			RawMatch: []rules.RawRule{{Type: "status", Value: 401}},
+		}},
+		MCP: &types.MCPConfig{},
+	}
+
+	s := NewScanner(WithTimeout(5*time.Second), WithMCPEnumeration(), WithOAuthExternal(), WithHeaders(map[string]string{"X-Scan-Token": "secret"}))
+	results := s.Scan(server.URL, []*types.Probe{p}, false)
+
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].MCP, "auth-gated servers are not enumerated")
+	om := results[0].OAuth
+	require.NotNil(t, om)
+	assert.Empty(t, om.Error)
+	assert.Equal(t, server.URL+"/.well-known/oauth-protected-resource/mcp", om.ResourceMetadataURL)
+	assert.Equal(t, resourceURL, om.Resource)
+	assert.Equal(t, []string{"mcp:read", "mcp:write"}, om.ScopesSupported)
+
+	require.Len(t, om.AuthorizationServers, 1)
+	as := om.AuthorizationServers[0]
+	assert.Equal(t, issuer, as.Issuer)
+	assert.True(t, as.ThirdParty)
+	assert.Equal(t, strings.TrimSuffix(issuer, "/tenant")+"/.well-known/openid-configuration/tenant", as.MetadataURL)
+	assert.Equal(t, "https://login.example.com/token", as.TokenEndpoint)
+
+	assert.Contains(t, om.Warnings, "authorization server "+issuer+" uses plain HTTP")
+	assert.Contains(t, om.Warnings, `authorization server `+issuer+` reports issuer "https://login.example.com/tenant"`)
+	assert.Contains(t, om.Warnings, "authorization server "+issuer+" does not support PKCE S256")
+
+	// Without WithOAuthExternal the off-host issuer is listed, not fetched.
+	results = NewScanner(WithTimeout(5*time.Second), WithMCPEnumeration()).Scan(server.URL, []*types.Probe{p}, false)
+	require.Len(t, results, 1)
+	require.NotNil(t, results[0].OAuth)
+	require.Len(t, results[0].OAuth.AuthorizationServers, 1)
+	as = results[0].OAuth.AuthorizationServers[0]
+	assert.True(t, as.ThirdParty)
+	assert.Empty(t, as.MetadataURL)
+	assert.Contains(t, as.Error, "not fetched")
+
+	results = NewScanner(WithTimeout(5*time.Second)).Scan(server.URL, []*types.Probe{p}, false)
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].OAuth, "discovery is opt-in")
+}
+
+func TestScan_OAuthDiscoveryLimits(t *testing.T) {
+	var fetched []string
+	var issuers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/offhost":
+			w.Header().Set("WWW-Authenticate", `Bearer resource_metadata="http://169.254.169.254/latest/meta-data"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/mcp":
+			w.Header().Set("WWW-Authenticate", `Bearer resource_metadata="/prm"`)
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/prm":
+			_ = json.NewEncoder(w).Encode(map[string]any{"resource": "x", "authorization_servers": issuers})
+		default:
+			fetched = append(fetched, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	for i := range 10 {
+		issuers = append(issuers, server.URL+"/issuer"+strconv.Itoa(i))
+	}
+
+	probe := func(path string) *types.Probe {
+		return &types.Probe{
+			Name:     "mcp-server",
+			Requests: []types.Request{{Path: path, RawMatch: []rules.RawRule{{Type: "status", Value: 401}}}},
+			MCP:      &types.MCPConfig{},
+		}
+	}
+	s := NewScanner(WithTimeout(5*time.Second), WithMCPEnumeration())
+
+	results := s.Scan(server.URL, []*types.Probe{probe("/offhost")}, false)
+	require.Len(t, results, 1)
+	require.NotNil(t, results[0].OAuth)
+	assert.Contains(t, results[0].OAuth.Error, "another host", "off-host metadata is not followed by default")
+
+	results = s.Scan(server.URL, []*types.Probe{probe("/mcp")}, false)
+	require.Len(t, results, 1)
+	require.NotNil(t, results[0].OAuth)
+	assert.Len(t, results[0].OAuth.AuthorizationServers, maxAuthorizationServers)
+	assert.Contains(t, results[0].OAuth.Warnings, "protected resource metadata lists 10 authorization servers; only the first 4 were fetched")
+	for _, path := range fetched {
+		assert.NotContains(t, path, "issuer4", "issuers past the cap are never requested")
+	}
+}
+
+func TestResourceMetadataURL(t *testing.T) {
+	got, err := resourceMetadataURL("https://mcp.example.com/v1/mcp", []string{`Bearer resource_metadata=https://meta.example.com/prm`})
+	require.NoError(t, err)
+	assert.Equal(t, "https://meta.example.com/prm", got, "unquoted parameter")
+
+	got, err = resourceMetadataURL("https://mcp.example.com/v1/mcp", []string{`Basic realm="x"`})
+	require.NoError(t, err)
+	assert.Equal(t, "https://mcp.example.com/.well-known/oauth-protected-resource/v1/mcp", got, "well-known fallback")
+}
+
+func TestAuthorizationServerMetadataURLs(t *testing.T) {
+	assert.Equal(t, []string{
+		"https://as.example.com/.well-known/oauth-authorization-server/tenant1",
+		"https://as.example.com/.well-known/openid-configuration/tenant1",
+		"https://as.example.com/tenant1/.well-known/openid-configuration",
+	}, authorizationServerMetadataURLs("https://as.example.com/tenant1"))
+
+	assert.Equal(t, []string{
+		"https://as.example.com/.well-known/oauth-authorization-server",
+		"https://as.example.com/.well-known/openid-configuration",
+	}, authorizationServerMetadataURLs("https://as.example.com/"))
+}
@@ -0,0 +1,181 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/itchyny/gojq"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// nextPagePath returns the path (relative to target) of the page after the
+// one just fetched, or ok=false when pagination should stop. page is the
+// zero-based index of the fetched page and pageModels how many model names it
+// yielded.
+func nextPagePath(target, path string, cfg *types.PaginationConfig, resp *http.Response, body []byte, page, pageModels int) (string, bool, error) {
+	if cfg.HasMore != "" {
+		v, err := evalJQ(body, cfg.HasMore)
+		if err != nil {
+			return "", false, fmt.Errorf("has_more: %w", err)
+		}
+		if more, _ := v.(bool); !more {
+			return "", false, nil
+		}
+	}
+
+	switch cfg.Type {
+	case types.PaginationCursor:
+		v, err := evalJQ(body, cfg.Next)
+		if err != nil {
+			return "", false, fmt.Errorf("next: %w", err)
+		}
+		cursor := scalarString(v)
+		if cursor == "" {
+			return "", false, nil
+		}
+		return setQueryParam(path, cfg.Param, cursor), true, nil
+
+	case types.PaginationPage:
+		// An empty page means we walked off the end of the listing.
+		if pageModels == 0 {
+			return "", false, nil
+		}
+		return setQueryParam(path, cfg.Param, strconv.Itoa(cfg.GetStart()+page+1)), true, nil
+
+	case types.PaginationLink:
+		var next string
+		if cfg.Next != "" {
+			v, err := evalJQ(body, cfg.Next)
+			if err != nil {
+				return "", false, fmt.Errorf("next: %w", err)
+			}
+			next = scalarString(v)
+		} else {
+			next = nextLink(resp.Header.Values("Link"))
+		}
+		if next == "" {
+			return "", false, nil
+		}
+		return relativeToTarget(target, path, next)
+
+	default:
+		return "", false, fmt.Errorf("unknown pagination type: %s", cfg.Type)
+	}
+}
+
+// evalJQ runs expr against the JSON body and returns its first output, or nil
+// when it produces none.
+func evalJQ(body []byte, expr string) (any, error) {
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	query, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid jq expression: %w", err)
+	}
+	return firstValue(query, data)
+}
+
+// scalarString renders a cursor value; cursors are strings or integers.
+func scalarString(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case int:
+		return strconv.Itoa(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// setQueryParam sets key=value on the path's query string, replacing any
+// existing value so page 1's parameter doesn't linger on page 2.
+func setQueryParam(path, key, value string) string {
+	u, err := url.Parse(path)
+	if err != nil {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+		return path + sep + url.QueryEscape(key) + "=" + url.QueryEscape(value)
+	}
+	q := u.Query()
+	q.Set(key, value)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// nextLink extracts the rel="next" URL from RFC 8288 Link header values.
+func nextLink(values []string) string {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			segments := strings.Split(link, ";")
+			ref := strings.TrimSpace(segments[0])
+			if !strings.HasPrefix(ref, "<") || !strings.HasSuffix(ref, ">") {
+				continue
+			}
+			for _, param := range segments[1:] {
+				name, val, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(name, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(val, `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return ref[1 : len(ref)-1]
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// relativeToTarget resolves a next-page reference against the current page
+// URL and returns it as a path under target. References that leave the target
+// are not followed: pagination must never redirect the scan, and the models
+// headers it carries (credentials included), to another origin. The next URL
+// must have the target's scheme and host, port included, no userinfo, and a
+// cleaned path at or below the target's path.
+func relativeToTarget(target, pagePath, ref string) (string, bool, error) {
+	origin, err := url.Parse(target)
+	if err != nil {
+		return "", false, fmt.Errorf("parsing target URL: %w", err)
+	}
+	base, err := url.Parse(target + pagePath)
+	if err != nil {
+		return "", false, fmt.Errorf("parsing page URL: %w", err)
+	}
+	next, err := base.Parse(ref)
+	if err != nil {
+		return "", false, fmt.Errorf("parsing next page URL %q: %w", ref, err)
+	}
+	if next.User != nil ||
+		!strings.EqualFold(next.Scheme, origin.Scheme) ||
+		!strings.EqualFold(next.Host, origin.Host) {
+		return "", false, nil
+	}
+
+	nextPath := path.Clean("/" + next.EscapedPath())
+	if strings.HasSuffix(next.EscapedPath(), "/") && nextPath != "/" {
+		nextPath += "/"
+	}
+	root := strings.TrimSuffix(path.Clean("/"+origin.EscapedPath()), "/")
+	if root != "" && nextPath != root && !strings.HasPrefix(nextPath, root+"/") {
+		return "", false, nil
+	}
+
+	rel := strings.TrimPrefix(nextPath, root)
+	if next.RawQuery != "" {
+		rel += "?" + next.RawQuery
+	}
+	return rel, true, nil
+}
@@ -0,0 +1,230 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+func TestFetchModels_CursorPagination(t *testing.T) {
+	pages := map[string]string{
+		"":   `{"data":[{"id":"a"},{"id":"b"}],"has_more":true,"last_id":"b"}`,
+		"b":  `{"data":[{"id":"c"}],"has_more":true,"last_id":"c"}`,
+		"c":  `{"data":[{"id":"d"}],"has_more":false,"last_id":"d"}`,
+		"zz": `{"data":[{"id":"never"}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(pages[r.URL.Query().Get("after")]))
+	}))
+	defer server.Close()
+
+	cfg := &types.ModelsConfig{
+		Path:    "/v1/models",
+		Extract: ".data[].id",
+		Pagination: &types.PaginationConfig{
+			Type:    types.PaginationCursor,
+			Param:   "after",
+			Next:    ".last_id",
+			HasMore: ".has_more",
+		},
+	}
+
+	models, _, err := NewScanner(WithTimeout(5*time.Second)).fetchModels(server.URL, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, models)
+}
+
+func TestFetchModels_CursorWithoutHasMoreStopsOnFirstPage(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"data":[{"id":"a"}]}`))
+	}))
+	defer server.Close()
+
+	cfg := &types.ModelsConfig{
+		Path:    "/v1/models",
+		Extract: ".data[].id",
+		Pagination: &types.PaginationConfig{
+			Type: types.PaginationCursor, Param: "after", Next: ".data[-1].id", HasMore: ".has_more",
+		},
+	}
+
+	models, _, err := NewScanner(WithTimeout(5*time.Second)).fetchModels(server.URL, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, models)
+	assert.Equal(t, 1, requests, "a server without has_more should not be paged")
+}
+
+func TestFetchModels_PageNumberPagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "", "1":
+			_, _ = w.Write([]byte(`["m1","m2"]`))
+		case "2":
+			_, _ = w.Write([]byte(`["m3"]`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	cfg := &types.ModelsConfig{
+		Path:       "/api/models?page=1",
+		Extract:    ".[]",
+		Pagination: &types.PaginationConfig{Type: types.PaginationPage, Param: "page"},
+	}
+
+	models, _, err := NewScanner(WithTimeout(5*time.Second)).fetchModels(server.URL, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m1", "m2", "m3"}, models)
+}
+
+func TestFetchModels_LinkHeaderPagination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			w.Header().Set("Link", `</api/models?cursor=p2>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"id":"org/one"}]`))
+		case "p2":
+			w.Header().Set("Link", `<https://elsewhere.example.com/api/models?cursor=p3>; rel="next"`)
+			_, _ = w.Write([]byte(`[{"id":"org/two"}]`))
+		}
+	}))
+	defer server.Close()
+
+	cfg := &types.ModelsConfig{
+		Path:       "/api/models",
+		Extract:    ".[].id",
+		Pagination: &types.PaginationConfig{Type: types.PaginationLink},
+	}
+
+	models, _, err := NewScanner(WithTimeout(5*time.Second)).fetchModels(server.URL, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"org/one", "org/two"}, models, "links to another host must not be followed")
+}
+
+func TestFetchModels_BodyLinkPagination(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			fmt.Fprintf(w, `{"next":"%s/v1/models?cursor=2","results":[{"owner":"meta","name":"llama"}]}`, server.URL)
+			return
+		}
+		_, _ = w.Write([]byte(`{"next":null,"results":[{"owner":"mistral","name":"small"}]}`))
+	}))
+	defer server.Close()
+
+	cfg := &types.ModelsConfig{
+		Path:       "/v1/models",
+		Extract:    `.results[] | .owner + "/" + .name`,
+		Pagination: &types.PaginationConfig{Type: types.PaginationLink, Next: ".next"},
+	}
+
+	models, _, err := NewScanner(WithTimeout(5*time.Second)).fetchModels(server.URL, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"meta/llama", "mistral/small"}, models)
+}
+
+func TestFetchModels_PaginationMaxPages(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		page := r.URL.Query().Get("page")
+		fmt.Fprintf(w, `["model-%s"]`, page)
+	}))
+	defer server.Close()
+
+	cfg := &types.ModelsConfig{
+		Path:       "/models",
+		Extract:    ".[]",
+		Pagination: &types.PaginationConfig{Type: types.PaginationPage, Param: "page", MaxPages: 3},
+	}
+
+	models, _, err := NewScanner(WithTimeout(5*time.Second)).fetchModels(server.URL, cfg)
+	require.NoError(t, err)
+	assert.Len(t, models, 3)
+	assert.Equal(t, 3, requests)
+}
+
+func TestFetchModels_LaterPageErrorKeepsEarlierModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			_, _ = w.Write([]byte(`["m1"]`))
+			return
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	cfg := &types.ModelsConfig{
+		Path:       "/models",
+		Extract:    ".[]",
+		Pagination: &types.PaginationConfig{Type: types.PaginationPage, Param: "page"},
+	}
+
+	models, _, err := NewScanner(WithTimeout(5*time.Second)).fetchModels(server.URL, cfg)
+	assert.ErrorContains(t, err, "429")
+	assert.Equal(t, []string{"m1"}, models)
+}
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   string
+	}{
+		{"single", []string{`<https://h/a?page=2>; rel="next"`}, "https://h/a?page=2"},
+		{"among others", []string{`<https://h/a?page=1>; rel="prev", <https://h/a?page=3>; rel="next"`}, "https://h/a?page=3"},
+		{"unquoted rel", []string{`</a?c=x>; rel=next`}, "/a?c=x"},
+		{"no next", []string{`<https://h/a>; rel="last"`}, ""},
+		{"none", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextLink(tt.values))
+		})
+	}
+}
+
+func TestSetQueryParam(t *testing.T) {
+	assert.Equal(t, "/v1/models?after=abc", setQueryParam("/v1/models", "after", "abc"))
+	assert.Equal(t, "/models?page=2", setQueryParam("/models?page=1", "page", "2"))
+	assert.Equal(t, "/openai/models?after=x&api-version=2024-10-21", setQueryParam("/openai/models?api-version=2024-10-21", "after", "x"))
+}
+
+func TestRelativeToTarget(t *testing.T) {
+	tests := []struct {
+		name, target, ref string
+		want              string
+		ok                bool
+	}{
+		{"relative", "https://api.example.com", "/v1/models?page=2", "/v1/models?page=2", true},
+		{"absolute same origin", "https://api.example.com", "https://api.example.com/v1/models?after=x", "/v1/models?after=x", true},
+		{"host case", "https://api.example.com", "https://API.example.com/v1/models", "/v1/models", true},
+		{"under base path", "https://h/api", "https://h/api/v1/models?page=2", "/v1/models?page=2", true},
+		{"host suffix", "https://api.example.com", "https://api.example.com.evil.net/v1/models", "", false},
+		{"userinfo", "https://api.example.com", "https://api.example.com@evil.net/x", "", false},
+		{"userinfo same host", "https://api.example.com", "https://user:pw@api.example.com/x", "", false},
+		{"port switch", "https://api.example.com", "https://api.example.com:8443/v1/models", "", false},
+		{"scheme switch", "https://api.example.com", "http://api.example.com/v1/models", "", false},
+		{"other host", "https://api.example.com", "https://evil.net/v1/models", "", false},
+		{"sibling of base path", "https://h/api", "https://h/apix/models", "", false},
+		{"dot segments out of base path", "https://h/api", "https://h/api/../admin", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := relativeToTarget(tt.target, "/v1/models", tt.ref)
+			require.NoError(t, err)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package scanner
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// Exchange is one request a probe sends and the response the target gave.
+type Exchange struct {
+	Method string
+	Path   string
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// Record sends every request of p, then the first page of its models
+// request, to target and returns the responses without matching them. SSE
+// requests are read up to their first event, as when scanning. Responses come
+// from the scanner's cache, so recording after Scan sends nothing new.
+func (s *Scanner) Record(target string, p *types.Probe) ([]Exchange, error) {
+	var exchanges []Exchange
+	for _, req := range p.Requests {
+		req.ApplyDefaults()
+		resp, body, err := s.doProbeRequest(target, req)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", req.Method, req.Path, err)
+		}
+		exchanges = append(exchanges, Exchange{Method: req.Method, Path: req.Path, Status: resp.StatusCode, Header: resp.Header, Body: body})
+	}
+
+	if m := p.Models; m != nil {
+		method := m.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		resp, body, err := s.doHTTPRequest(target, method, m.Path, m.Body, m.Headers)
+		if err != nil {
+			return nil, fmt.Errorf("models %s %s: %w", method, m.Path, err)
+		}
+		exchanges = append(exchanges, Exchange{Method: method, Path: m.Path, Status: resp.StatusCode, Header: resp.Header, Body: body})
+	}
+	return exchanges, nil
+}
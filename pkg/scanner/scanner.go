@@ -5,7 +5,9 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -14,6 +16,7 @@ import (
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/singleflight"
 
+	"github.com/praetorian-inc/julius/pkg/credentials"
 	"github.com/praetorian-inc/julius/pkg/probe"
 	"github.com/praetorian-inc/julius/pkg/types"
 )
@@ -30,6 +33,11 @@ type Scanner struct {
 	concurrency     int
 	maxResponseSize int64
 	headers         map[string]string
+	credentials     *credentials.Store
+	verifyInference bool
+	mcpEnumerate    bool
+	mcpDiscover     bool
+	oauthExternal   bool
 }
 
 type Option func(*Scanner)
@@ -63,6 +71,8 @@ func (s *Scanner) Scan(target string, probes []*types.Probe, augustus bool) []ty
 		resultsMu sync.Mutex
 	)
 
+	probes = s.withMCPDiscovery(target, probes)
+
 	g, ctx := errgroup.WithContext(context.Background())
 	g.SetLimit(s.concurrency)
 
@@ -85,18 +95,42 @@ func (s *Scanner) Scan(target string, probes []*types.Probe, augustus bool) []ty
 				MatchedRequest: matchedReq.Path,
 				Category:       p.Category,
 				Specificity:    p.GetSpecificity(),
+				Auth:           p.AuthPosture(matchedReq),
+				Timestamp:      time.Now().UTC(),
 			}
 
+			cred := s.credentials.Lookup(target, p.Name)
+
 			if p.Models != nil {
-				models, err := s.fetchModels(target, p.Models)
+				models, details, err := s.fetchModels(target, withCredential(p.Models, cred))
 				if err != nil {
 					result.Error = err.Error()
 				}
 				result.Models = models
+				result.ModelDetails = details
 			}
 
 			if augustus {
-				result.GeneratorConfigs = p.BuildGeneratorConfigs(target, result.Models)
+				var apiKey string
+				if cred != nil {
+					apiKey = cred.APIKey
+				}
+				result.GeneratorConfigs = p.BuildGeneratorConfigsWithAPIKey(target, result.Models, apiKey)
+			}
+
+			if s.mcpEnumerate && p.MCP != nil {
+				if result.Auth == types.AuthRequired {
+					result.OAuth = s.discoverOAuth(target, matchedReq)
+				} else {
+					result.MCP = s.enumerateMCP(target+matchedReq.Path, p.MCP, cred)
+				}
+			}
+
+			if s.verifyInference {
+				result.Inference = s.checkInference(p, target, result.Models)
+				if result.Inference != nil && result.Inference.Verified {
+					result.Auth = types.AuthOpen
+				}
 			}
 
 			resultsMu.Lock()
@@ -162,7 +196,7 @@ func (s *Scanner) matchProbeAll(target string, p *types.Probe) (bool, types.Requ
 }
 
 func (s *Scanner) DoRequest(target string, req types.Request) (bool, error) {
-	resp, body, err := s.doHTTPRequest(target, req.Method, req.Path, req.Body, req.Headers)
+	resp, body, err := s.doProbeRequest(target, req)
 	if err != nil {
 		return false, fmt.Errorf("executing request: %w", err)
 	}
@@ -176,20 +210,108 @@ func (s *Scanner) DoRequest(target string, req types.Request) (bool, error) {
 	return matched, nil
 }
 
-func (s *Scanner) fetchModels(target string, cfg *types.ModelsConfig) ([]string, error) {
-	resp, body, err := s.doHTTPRequest(target, cfg.Method, cfg.Path, cfg.Body, cfg.Headers)
-	if err != nil {
-		return nil, fmt.Errorf("models request failed: %w", err)
+func (s *Scanner) fetchModels(target string, cfg *types.ModelsConfig) ([]string, []types.ModelDetail, error) {
+	maxPages := 1
+	if cfg.Pagination != nil {
+		maxPages = cfg.Pagination.GetMaxPages()
+	}
+
+	var (
+		models  []string
+		details []types.ModelDetail
+		seen    = make(map[string]bool)
+		path    = cfg.Path
+	)
+
+	for page := 0; page < maxPages; page++ {
+		seen[path] = true
+
+		resp, body, err := s.doHTTPRequest(target, cfg.Method, path, cfg.Body, cfg.Headers)
+		if err != nil {
+			return models, details, fmt.Errorf("models request failed: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			return models, details, fmt.Errorf("models request returned %d", resp.StatusCode)
+		}
+
+		pageModels, err := extractModels(body, cfg.Extract)
+		if err != nil {
+			return models, details, err
+		}
+		models = appendUnique(models, pageModels)
+
+		if cfg.Items != "" && len(cfg.Fields) > 0 {
+			pageDetails, err := extractModelDetails(body, cfg.Items, cfg.Fields)
+			if err != nil {
+				// The model names are still good; report the detail failure alongside them.
+				return models, details, fmt.Errorf("extracting model details: %w", err)
+			}
+			details = append(details, pageDetails...)
+		}
+
+		if cfg.Pagination == nil {
+			break
+		}
+
+		next, ok, err := nextPagePath(target, path, cfg.Pagination, resp, body, page, len(pageModels))
+		if err != nil {
+			return models, details, fmt.Errorf("models pagination: %w", err)
+		}
+		// A page pointing back at one already fetched would loop until max_pages.
+		if !ok || seen[next] {
+			break
+		}
+		if page == maxPages-1 {
+			slog.Warn("Models pagination stopped at max_pages", "target", target, "max_pages", maxPages)
+		}
+		path = next
+	}
+
+	if models == nil {
+		models = []string{}
+	}
+	return models, details, nil
+}
+
+// withCredential returns cfg with the credential's auth headers layered over
+// the probe's own models headers. cfg is shared by every target, so it is
+// copied rather than modified.
+func withCredential(cfg *types.ModelsConfig, cred *credentials.Credential) *types.ModelsConfig {
+	if cred == nil {
+		return cfg
 	}
+	authed := *cfg
+	authed.Headers = make(map[string]string, len(cfg.Headers))
+	for k, v := range cfg.Headers {
+		authed.Headers[k] = v
+	}
+	for k, v := range cred.AuthHeaders() {
+		authed.Headers[k] = v
+	}
+	return &authed
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("models request returned %d", resp.StatusCode)
+// appendUnique appends the elements of add not already in list.
+func appendUnique(list, add []string) []string {
+	for _, s := range add {
+		if !slices.Contains(list, s) {
+			list = append(list, s)
+		}
 	}
+	return list
+}
 
-	return extractModels(body, cfg.Extract)
+// doProbeRequest sends a probe request, honoring its type.
+func (s *Scanner) doProbeRequest(target string, req types.Request) (*http.Response, []byte, error) {
+	return s.sendRequest(target, req.Method, req.Path, req.Body, req.Headers, req.Type == types.RequestTypeSSE)
 }
 
 func (s *Scanner) doHTTPRequest(target, method, path, body string, headers map[string]string) (*http.Response, []byte, error) {
+	return s.sendRequest(target, method, path, body, headers, false)
+}
+
+func (s *Scanner) sendRequest(target, method, path, body string, headers map[string]string, firstEvent bool) (*http.Response, []byte, error) {
 	if method == "" {
 		method = "GET"
 	}
@@ -217,7 +339,7 @@ func (s *Scanner) doHTTPRequest(target, method, path, body string, headers map[s
 		req.Header.Set(key, value)
 	}
 
-	return s.cachedRequest(req, bodyBytes)
+	return s.cachedRequest(req, bodyBytes, firstEvent)
 }
 
 func WithTimeout(d time.Duration) Option {
@@ -257,3 +379,45 @@ func WithHeaders(headers map[string]string) Option {
 		s.headers = headers
 	}
 }
+
+// WithCredentials authenticates models requests and resolves $API_KEY in
+// generator configs with the credential matching each target and service.
+func WithCredentials(store *credentials.Store) Option {
+	return func(s *Scanner) {
+		s.credentials = store
+	}
+}
+
+// WithInferenceVerification sends one minimal, unauthenticated completion
+// request per match to prove the endpoint serves inference.
+func WithInferenceVerification() Option {
+	return func(s *Scanner) {
+		s.verifyInference = true
+	}
+}
+
+// WithMCPEnumeration completes an MCP session on every open match of a probe
+// with an mcp section and records the server's tools, resources and prompts.
+// Auth-gated matches instead record the server's OAuth metadata.
+func WithMCPEnumeration() Option {
+	return func(s *Scanner) {
+		s.mcpEnumerate = true
+	}
+}
+
+// WithOAuthExternal lets the OAuth discovery of WithMCPEnumeration follow
+// metadata and authorization server links to hosts other than the target.
+func WithOAuthExternal() Option {
+	return func(s *Scanner) {
+		s.oauthExternal = true
+	}
+}
+
+// WithMCPDiscovery also tries every probe with an mcp section at its
+// discovery paths and at the path named by the target's RFC 9728 well-known
+// metadata.
+func WithMCPDiscovery() Option {
+	return func(s *Scanner) {
+		s.mcpDiscover = true
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/praetorian-inc/julius/pkg/credentials"
 	"github.com/praetorian-inc/julius/pkg/rules"
 	"github.com/praetorian-inc/julius/pkg/types"
 	"github.com/stretchr/testify/assert"
@@ -188,6 +189,9 @@ func TestScan_ReturnsAllMatches(t *testing.T) {
 	assert.Equal(t, 75, results[0].Specificity)
 	assert.Equal(t, "generic-service", results[1].Service)
 	assert.Equal(t, 1, results[1].Specificity)
+	for _, r := range results {
+		assert.False(t, r.Timestamp.IsZero(), "each result should record when it matched")
+	}
 }
 
 func TestScan_SortsBySpecificity(t *testing.T) {
@@ -545,7 +549,7 @@ func TestFetchModels(t *testing.T) {
 			defer server.Close()
 
 			scanner := NewScanner(WithTimeout(5*time.Second))
-			models, err := scanner.fetchModels(server.URL, tt.config)
+			models, _, err := scanner.fetchModels(server.URL, tt.config)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -575,7 +579,7 @@ func TestFetchModelsWithHeaders(t *testing.T) {
 		Extract: ".data[].id",
 	}
 
-	_, err := scanner.fetchModels(server.URL, cfg)
+	_, _, err := scanner.fetchModels(server.URL, cfg)
 	require.NoError(t, err)
 	assert.Equal(t, "Bearer test-token", receivedAuth)
 }
@@ -1401,3 +1405,121 @@ func TestResponseSizeTruncation(t *testing.T) {
 	// Body should be truncated to maxResponseSize
 	assert.Equal(t, 512, len(body), "response body should be truncated at size limit")
 }
+
+func TestExtractModelDetails(t *testing.T) {
+	body := `{"models":[
+		{"name":"llama3.2:latest","size":2019393189,"details":{"family":"llama","parameter_size":"3.2B","quantization_level":"Q4_K_M"}},
+		{"name":"nomic-embed-text","size":274302450,"details":{"family":"nomic-bert"}}
+	]}`
+	fields := map[string]string{
+		"name":           ".name",
+		"size":           ".size",
+		"family":         ".details.family",
+		"parameter_size": ".details.parameter_size",
+		"quantization":   ".details.quantization_level",
+	}
+
+	details, err := extractModelDetails([]byte(body), ".models[]", fields)
+	require.NoError(t, err)
+	require.Len(t, details, 2)
+
+	assert.Equal(t, "llama3.2:latest", details[0].Name)
+	assert.Equal(t, map[string]any{
+		"size":           float64(2019393189),
+		"family":         "llama",
+		"parameter_size": "3.2B",
+		"quantization":   "Q4_K_M",
+	}, details[0].Fields)
+
+	assert.Equal(t, "nomic-embed-text", details[1].Name)
+	assert.NotContains(t, details[1].Fields, "quantization", "null fields should be omitted")
+}
+
+func TestExtractModelDetails_Errors(t *testing.T) {
+	_, err := extractModelDetails([]byte(`not json`), ".[]", map[string]string{"name": ".id"})
+	assert.Error(t, err)
+
+	_, err = extractModelDetails([]byte(`[]`), ".[invalid", map[string]string{"name": ".id"})
+	assert.Error(t, err)
+
+	_, err = extractModelDetails([]byte(`[]`), ".[]", map[string]string{"name": ".[invalid"})
+	assert.Error(t, err)
+}
+
+func TestScanWithModelDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"Qwen/Qwen2.5-7B","object":"model","created":1700000000,"owned_by":"vllm","max_model_len":32768}]}`))
+	}))
+	defer server.Close()
+
+	p := &types.Probe{
+		Name: "vllm",
+		Requests: []types.Request{{
+			Path:     "/v1/models",
+			RawMatch: []rules.RawRule{{Type: "status", Value: 200}},
+		}},
+		Models: &types.ModelsConfig{
+			Path:    "/v1/models",
+			Extract: ".data[].id",
+			Items:   ".data[]",
+			Fields: map[string]string{
+				"name":          ".id",
+				"owned_by":      ".owned_by",
+				"max_model_len": ".max_model_len",
+			},
+		},
+	}
+
+	results := NewScanner(WithTimeout(5*time.Second)).Scan(server.URL, []*types.Probe{p}, false)
+	require.Len(t, results, 1)
+	assert.Equal(t, []string{"Qwen/Qwen2.5-7B"}, results[0].Models)
+	assert.Equal(t, []types.ModelDetail{{
+		Name:   "Qwen/Qwen2.5-7B",
+		Fields: map[string]any{"owned_by": "vllm", "max_model_len": float64(32768)},
+	}}, results[0].ModelDetails)
+	assert.Empty(t, results[0].Error)
+}
+
+func TestScan_CredentialsAuthenticateModelsAndResolveAPIKey(t *testing.T) {
+	var detectAuth, modelsAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			detectAuth = r.Header.Get("api-key")
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/models":
+			modelsAuth = r.Header.Get("api-key")
+			if modelsAuth != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[{"id":"gpt-4o"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	store, err := credentials.Parse([]byte("credentials:\n  - service: azure\n    api_key: secret\n    headers:\n      api-key: $API_KEY\n"))
+	require.NoError(t, err)
+
+	p := &types.Probe{
+		Name:     "azure",
+		Requests: []types.Request{{Path: "/", RawMatch: []rules.RawRule{{Type: "status", Value: 401}}}},
+		Models:   &types.ModelsConfig{Path: "/models", Extract: ".data[].id"},
+		Augustus: &types.AugustusConfig{
+			Generator:      "openai",
+			ConfigTemplate: types.GeneratorConfig{Endpoint: "$TARGET", Model: "$MODEL", APIKey: "$API_KEY"},
+		},
+	}
+
+	s := NewScanner(WithTimeout(5*time.Second), WithCredentials(store))
+	results := s.Scan(server.URL, []*types.Probe{p}, true)
+
+	require.Len(t, results, 1)
+	assert.Empty(t, detectAuth, "detection requests must stay unauthenticated")
+	assert.Equal(t, "secret", modelsAuth)
+	assert.Equal(t, []string{"gpt-4o"}, results[0].Models)
+	require.Len(t, results[0].GeneratorConfigs, 1)
+	assert.Equal(t, "secret", results[0].GeneratorConfigs[0].APIKey)
+	assert.Nil(t, p.Models.Headers, "the shared probe config must not be modified")
+}
@@ -0,0 +1,280 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+// verifyPrompt is sent by --verify-inference. Completions are capped at one
+// token where the API allows it, so a check costs the target next to nothing.
+const verifyPrompt = "Reply with OK."
+
+// inferenceRequest is one completion request plus the parser that decides
+// whether its response carried a completion.
+type inferenceRequest struct {
+	method  string
+	url     string
+	headers map[string]string
+	body    []byte
+	// answered reports whether the decoded body holds a completion and, if
+	// the server says so, which model produced it.
+	answered func(body []byte) (model string, ok bool)
+}
+
+// checkInference sends one completion request for the first discovered
+// model, built from the probe's generator template, and reports whether it
+// was served. Credentials and -H headers are never sent: the point is to
+// prove the endpoint is usable by anyone. Probes without an augustus template
+// return nil.
+func (s *Scanner) checkInference(p *types.Probe, target string, models []string) *types.InferenceCheck {
+	if p.Augustus == nil {
+		return nil
+	}
+
+	configs := p.BuildGeneratorConfigs(target, models)
+	if len(configs) == 0 {
+		return nil
+	}
+	cfg := configs[0]
+	check := &types.InferenceCheck{}
+
+	if strings.Contains(cfg.Model, "$MODEL") || strings.Contains(cfg.Endpoint, "$MODEL") {
+		check.Error = "no model discovered to verify with"
+		return check
+	}
+
+	ir, err := buildInferenceRequest(cfg)
+	if err != nil {
+		check.Error = err.Error()
+		return check
+	}
+
+	req, err := http.NewRequest(ir.method, ir.url, bytes.NewReader(ir.body))
+	if err != nil {
+		check.Error = fmt.Sprintf("creating request: %v", err)
+		return check
+	}
+	for k, v := range ir.headers {
+		req.Header.Set(k, v)
+	}
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		check.LatencyMS = time.Since(start).Milliseconds()
+		check.Error = err.Error()
+		return check
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, s.maxResponseSize))
+	_ = resp.Body.Close()
+	check.LatencyMS = time.Since(start).Milliseconds()
+	check.Status = resp.StatusCode
+	if err != nil {
+		check.Error = fmt.Sprintf("reading response: %v", err)
+		return check
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		check.Error = fmt.Sprintf("completion request returned %d", resp.StatusCode)
+		return check
+	}
+	model, ok := ir.answered(body)
+	if !ok {
+		check.Error = "response did not contain a completion"
+		return check
+	}
+	check.Verified = true
+	check.Model = model
+	if check.Model == "" {
+		check.Model = cfg.Model
+	}
+	return check
+}
+
+// buildInferenceRequest maps a resolved generator config onto the smallest
+// completion request its API accepts. Only generator types with a known
+// request shape are supported.
+func buildInferenceRequest(cfg types.GeneratorConfig) (*inferenceRequest, error) {
+	messages := []map[string]string{{"role": "user", "content": verifyPrompt}}
+
+	switch cfg.Type {
+	case "openai":
+		body, err := json.Marshal(map[string]any{
+			"model":      cfg.Model,
+			"messages":   messages,
+			"max_tokens": 1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &inferenceRequest{
+			method:  http.MethodPost,
+			url:     strings.TrimRight(types.OpenAIBaseURL(cfg.Endpoint), "/") + "/chat/completions",
+			headers: map[string]string{"Content-Type": "application/json"},
+			body:    body,
+			answered: func(body []byte) (string, bool) {
+				var resp struct {
+					Model   string            `json:"model"`
+					Choices []json.RawMessage `json:"choices"`
+				}
+				if json.Unmarshal(body, &resp) != nil || len(resp.Choices) == 0 {
+					return "", false
+				}
+				return resp.Model, true
+			},
+		}, nil
+
+	case "ollama":
+		body, err := json.Marshal(map[string]any{
+			"model":    cfg.Model,
+			"messages": messages,
+			"stream":   false,
+			"options":  map[string]any{"num_predict": 1},
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &inferenceRequest{
+			method:  http.MethodPost,
+			url:     strings.TrimRight(cfg.Endpoint, "/") + "/api/chat",
+			headers: map[string]string{"Content-Type": "application/json"},
+			body:    body,
+			answered: func(body []byte) (string, bool) {
+				var resp struct {
+					Model   string          `json:"model"`
+					Message json.RawMessage `json:"message"`
+				}
+				if json.Unmarshal(body, &resp) != nil || len(resp.Message) == 0 {
+					return "", false
+				}
+				return resp.Model, true
+			},
+		}, nil
+
+	case "rest":
+		if cfg.Body == "" {
+			return nil, fmt.Errorf("rest generator template has no body")
+		}
+		// Without a response_path any 2xx body would pass for a completion.
+		if cfg.ResponsePath == "" {
+			return nil, fmt.Errorf("rest generator template has no response_path to find a completion with")
+		}
+		body, err := capTokenLimits([]byte(strings.ReplaceAll(cfg.Body, types.PromptVar, verifyPrompt)))
+		if err != nil {
+			return nil, err
+		}
+		headers := make(map[string]string, len(cfg.Headers))
+		for k, v := range cfg.Headers {
+			// A header still carrying a placeholder is a credential slot.
+			if !strings.Contains(v, "$") {
+				headers[k] = v
+			}
+		}
+		method := cfg.Method
+		if method == "" {
+			method = http.MethodPost
+		}
+		return &inferenceRequest{
+			method:  method,
+			url:     cfg.Endpoint,
+			headers: headers,
+			body:    body,
+			answered: func(body []byte) (string, bool) {
+				var decoded any
+				if json.Unmarshal(body, &decoded) != nil {
+					return "", false
+				}
+				value, ok := lookupPath(decoded, cfg.ResponsePath)
+				return "", ok && value != nil
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("inference verification is not supported for %q generators", cfg.Type)
+}
+
+// tokenLimitKeys are the request fields completion APIs read their output
+// limit from, lowercased with "_" and "-" removed.
+var tokenLimitKeys = map[string]bool{
+	"maxtokens":           true,
+	"maxnewtokens":        true,
+	"maxcompletiontokens": true,
+	"maxoutputtokens":     true,
+	"maxtokenstosample":   true,
+	"maxlength":           true,
+	"maxgenlen":           true,
+	"numpredict":          true,
+	"npredict":            true,
+}
+
+var tokenLimitKeyNormalizer = strings.NewReplacer("_", "", "-", "")
+
+// capTokenLimits sets every numeric token-limit field of a rest template's
+// JSON body to 1, so a verification costs no more than the built-in request
+// shapes. A body without such fields is returned as written; one that is not
+// JSON is refused, since its limit cannot be checked.
+func capTokenLimits(body []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return nil, fmt.Errorf("rest generator body is not JSON, so its token limit cannot be capped")
+	}
+
+	capped := false
+	var walk func(v any)
+	walk = func(v any) {
+		switch node := v.(type) {
+		case map[string]any:
+			for k, child := range node {
+				key := tokenLimitKeyNormalizer.Replace(strings.ToLower(k))
+				if _, isNumber := child.(json.Number); isNumber && tokenLimitKeys[key] {
+					node[k] = 1
+					capped = true
+					continue
+				}
+				walk(child)
+			}
+		case []any:
+			for _, child := range node {
+				walk(child)
+			}
+		}
+	}
+	walk(v)
+	if !capped {
+		return body, nil
+	}
+	return json.Marshal(v)
+}
+
+// lookupPath walks a dotted response_path (e.g. "choices.0.text") through
+// decoded JSON.
+func lookupPath(v any, path string) (any, bool) {
+	for _, part := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			next, ok := node[part]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
@@ -0,0 +1,168 @@
+package scanner
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/praetorian-inc/julius/pkg/rules"
+	"github.com/praetorian-inc/julius/pkg/types"
+)
+
+func openAIVerifyProbe() *types.Probe {
+	return &types.Probe{
+		Name: "openai-like",
+		Requests: []types.Request{{
+			Path:     "/v1/models",
+			RawMatch: []rules.RawRule{{Type: "status", Value: 401}},
+		}},
+		Augustus: &types.AugustusConfig{
+			Generator: "openai",
+			ConfigTemplate: types.GeneratorConfig{
+				Endpoint: "$TARGET",
+				Model:    "$MODEL",
+				APIKey:   "$API_KEY",
+			},
+		},
+	}
+}
+
+func TestScan_VerifyInference_OpenAI(t *testing.T) {
+	var chatBody map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/models":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/v1/chat/completions":
+			assert.Empty(t, r.Header.Get("Authorization"), "verification must be unauthenticated")
+			data, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(data, &chatBody)
+			_, _ = w.Write([]byte(`{"model":"llama-3-8b","choices":[{"message":{"content":"OK"}}]}`))
+		}
+	}))
+	defer server.Close()
+
+	p := openAIVerifyProbe()
+	p.Augustus.ConfigTemplate.Model = "llama-3-8b"
+
+	s := NewScanner(WithTimeout(5*time.Second), WithInferenceVerification(), WithHeaders(map[string]string{"Authorization": "Bearer user"}))
+	results := s.Scan(server.URL, []*types.Probe{p}, false)
+
+	require.Len(t, results, 1)
+	inf := results[0].Inference
+	require.NotNil(t, inf)
+	assert.True(t, inf.Verified)
+	assert.Equal(t, "llama-3-8b", inf.Model)
+	assert.Equal(t, http.StatusOK, inf.Status)
+	assert.Empty(t, inf.Error)
+	assert.Equal(t, types.AuthOpen, results[0].Auth, "a served completion proves the endpoint is open")
+	assert.EqualValues(t, 1, chatBody["max_tokens"])
+}
+
+func TestScan_VerifyInference_Rejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	p := openAIVerifyProbe()
+	p.Augustus.ConfigTemplate.Model = "gpt-4o"
+
+	results := NewScanner(WithTimeout(5*time.Second), WithInferenceVerification()).Scan(server.URL, []*types.Probe{p}, false)
+
+	require.Len(t, results, 1)
+	inf := results[0].Inference
+	require.NotNil(t, inf)
+	assert.False(t, inf.Verified)
+	assert.Equal(t, http.StatusUnauthorized, inf.Status)
+	assert.Equal(t, "completion request returned 401", inf.Error)
+	assert.Equal(t, types.AuthRequired, results[0].Auth)
+}
+
+func TestScan_VerifyInference_NeedsModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	results := NewScanner(WithTimeout(5*time.Second), WithInferenceVerification()).Scan(server.URL, []*types.Probe{openAIVerifyProbe()}, false)
+
+	require.Len(t, results, 1)
+	require.NotNil(t, results[0].Inference)
+	assert.Equal(t, "no model discovered to verify with", results[0].Inference.Error)
+}
+
+func TestScan_VerifyInference_Disabled(t *testing.T) {
+	completions := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/chat/completions" {
+			completions++
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	p := openAIVerifyProbe()
+	p.Augustus.ConfigTemplate.Model = "gpt-4o"
+	results := NewScanner(WithTimeout(5*time.Second)).Scan(server.URL, []*types.Probe{p}, false)
+
+	require.Len(t, results, 1)
+	assert.Nil(t, results[0].Inference)
+	assert.Zero(t, completions)
+}
+
+func TestBuildInferenceRequest_Rest(t *testing.T) {
+	ir, err := buildInferenceRequest(types.GeneratorConfig{
+		Type:         "rest",
+		Endpoint:     "http://host/generate",
+		Headers:      map[string]string{"Content-Type": "application/json", "Authorization": "Bearer $API_KEY"},
+		Body:         `{"inputs": "$PROMPT"}`,
+		ResponsePath: "choices.0.text",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, ir.method)
+	assert.Equal(t, `{"inputs": "Reply with OK."}`, string(ir.body))
+	assert.Equal(t, map[string]string{"Content-Type": "application/json"}, ir.headers, "credential slots are dropped")
+
+	_, ok := ir.answered([]byte(`{"choices":[{"text":"OK"}]}`))
+	assert.True(t, ok)
+	_, ok = ir.answered([]byte(`{"error":"unauthorized"}`))
+	assert.False(t, ok)
+
+	_, err = buildInferenceRequest(types.GeneratorConfig{Type: "mcp"})
+	assert.Error(t, err)
+}
+
+func TestBuildInferenceRequest_RestCapsTokenLimits(t *testing.T) {
+	ir, err := buildInferenceRequest(types.GeneratorConfig{
+		Type:         "rest",
+		Endpoint:     "http://host/generate",
+		Body:         `{"inputs": "$PROMPT", "parameters": {"max_new_tokens": 512, "temperature": 0.7}}`,
+		ResponsePath: "generated_text",
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"inputs": "Reply with OK.", "parameters": {"max_new_tokens": 1, "temperature": 0.7}}`, string(ir.body))
+
+	_, err = buildInferenceRequest(types.GeneratorConfig{
+		Type:         "rest",
+		Endpoint:     "http://host/generate",
+		Body:         `prompt=$PROMPT&max_tokens=512`,
+		ResponsePath: "text",
+	})
+	assert.ErrorContains(t, err, "not JSON")
+}
+
+func TestBuildInferenceRequest_RestNeedsResponsePath(t *testing.T) {
+	_, err := buildInferenceRequest(types.GeneratorConfig{
+		Type:     "rest",
+		Endpoint: "http://host/generate",
+		Body:     `{"inputs": "$PROMPT"}`,
+	})
+	assert.ErrorContains(t, err, "no response_path")
+}
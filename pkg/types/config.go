@@ -1,5 +1,15 @@
 package types
 
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"slices"
+	"strings"
+)
+
 type AugustusConfig struct {
 	Generator      string          `yaml:"generator"`
 	ConfigTemplate GeneratorConfig `yaml:"config_template"`
@@ -20,15 +30,181 @@ type GeneratorConfig struct {
 	// Extra is a generator-type-specific passthrough for config keys that have no
 	// dedicated field above (which are shaped for HTTP/REST LLM generators). It
 	// exists for generators like the augustus MCP generator, whose config is
-	// carried entirely in extra keys (transport, mode). Values support the same
+	// carried entirely in extra keys (transport, mode). Values keep their YAML
+	// types — strings, bools, numbers, lists and nested maps — through to JSON
+	// output, so `verify_tls: false` reaches the generator as a bool. String
+	// values, including strings nested in lists and maps, support the same
 	// $TARGET/$MODEL substitution as the typed fields — with the same caveat that
-	// $MODEL is only substituted when the probe declares `models:`; with no models
-	// (e.g. mcp-server) a `$MODEL`-valued extra stays the literal `$MODEL`, so
-	// don't rely on it there. Like Headers, extra values are NOT rewritten by
-	// --base-paths (only Endpoint is), so avoid URL-valued extras.
-	// Values are strings only: a non-string YAML value (e.g. `verify_tls: false`)
-	// is coerced to its string form, which a bool/int-expecting generator reads as
-	// its default with no error. map[string]any is the eventual home for typed
-	// keys (LAB-4935).
-	Extra map[string]string `yaml:"extra,omitempty" json:"extra,omitempty"`
+	// $MODEL is only substituted when the probe declares `models:`; with no
+	// models (e.g. mcp-server) a `$MODEL`-valued extra stays the literal
+	// `$MODEL`, so don't rely on it there. Like Headers, extra values are NOT
+	// rewritten by --base-paths (only Endpoint is), so avoid URL-valued extras.
+	// ValidateExtra lists what a generator cannot accept.
+	Extra map[string]any `yaml:"extra,omitempty" json:"extra,omitempty"`
+}
+
+// PromptVar is the prompt slot in generator request bodies. Augustus fills it
+// at attack time; julius leaves it in place.
+const PromptVar = "$PROMPT"
+
+// OpenAIBaseURL returns the base URL an OpenAI client appends
+// /chat/completions to. Augustus openai configs usually carry the bare
+// $TARGET, which clients expect as <target>/v1; endpoints that already carry
+// a path are used as-is.
+func OpenAIBaseURL(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || strings.Trim(u.Path, "/") != "" {
+		return endpoint
+	}
+	return strings.TrimRight(endpoint, "/") + "/v1"
+}
+
+// extraReservedKeys are the typed GeneratorConfig fields. An extra key with
+// one of these names would emit a second, conflicting value for the same
+// setting, so it is rejected rather than silently shadowing the field.
+var extraReservedKeys = map[string]bool{
+	"type": true, "endpoint": true, "api_key": true, "model": true, "method": true,
+	"headers": true, "body": true, "response_path": true, "content_type": true,
+	"proxy": true, "timeout": true,
+}
+
+// extraKind is the Go kind a generator expects for a known extra key.
+type extraKind string
+
+const (
+	extraString extraKind = "string"
+	extraBool   extraKind = "bool"
+	extraInt    extraKind = "integer"
+)
+
+// Generators lists the Augustus generator types probes may emit configs for.
+var Generators = []string{"openai", "ollama", "rest", "mcp"}
+
+// extraKeyKinds lists extra keys whose type is known, per generator. The ""
+// entry applies to every generator. Keys not listed pass through with any
+// JSON-representable value.
+var extraKeyKinds = map[string]map[string]extraKind{
+	"": {
+		"verify_tls":  extraBool,
+		"max_retries": extraInt,
+	},
+	"mcp": {
+		"transport": extraString,
+		"mode":      extraString,
+	},
+}
+
+// ValidateExtra reports extra values the given generator cannot accept: keys
+// that shadow a typed field, nulls, values with no JSON representation (NaN,
+// infinities, maps with non-string keys) and known keys of the wrong type.
+func ValidateExtra(generator string, extra map[string]any) error {
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(extra)) {
+		value := extra[key]
+		if extraReservedKeys[key] {
+			errs = append(errs, fmt.Errorf("extra.%s: use the %s field instead", key, key))
+			continue
+		}
+		if err := validateExtraValue("extra."+key, value); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		kind, ok := extraKeyKinds[generator][key]
+		if !ok {
+			kind, ok = extraKeyKinds[""][key]
+		}
+		if ok && !isExtraKind(value, kind) {
+			errs = append(errs, fmt.Errorf("extra.%s: %s generator expects type %s, got %T", key, generatorLabel(generator), kind, value))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func generatorLabel(generator string) string {
+	if generator == "" {
+		return "the"
+	}
+	return generator
+}
+
+func validateExtraValue(path string, value any) error {
+	switch v := value.(type) {
+	case nil:
+		return fmt.Errorf("%s: null is not a valid value; omit the key instead", path)
+	case string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return nil
+	case float32:
+		return validateExtraValue(path, float64(v))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%s: %v cannot be represented in JSON", path, v)
+		}
+		return nil
+	case []any:
+		for i, item := range v {
+			if err := validateExtraValue(fmt.Sprintf("%s[%d]", path, i), item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			if err := validateExtraValue(path+"."+key, v[key]); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%s: unsupported value of type %T (maps must have string keys)", path, value)
+	}
+}
+
+func isExtraKind(value any, kind extraKind) bool {
+	switch kind {
+	case extraString:
+		_, ok := value.(string)
+		return ok
+	case extraBool:
+		_, ok := value.(bool)
+		return ok
+	case extraInt:
+		switch v := value.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			return true
+		case float64:
+			return v == math.Trunc(v)
+		}
+	}
+	return false
+}
+
+// MapExtra returns a deep copy of extra with fn applied to every string value,
+// including strings nested in lists and maps. Non-string values are copied
+// unchanged, so bools and numbers keep their types.
+func MapExtra(extra map[string]any, fn func(string) string) map[string]any {
+	if extra == nil {
+		return nil
+	}
+	return mapExtraValue(extra, fn).(map[string]any)
+}
+
+func mapExtraValue(value any, fn func(string) string) any {
+	switch v := value.(type) {
+	case string:
+		return fn(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = mapExtraValue(item, fn)
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = mapExtraValue(item, fn)
+		}
+		return out
+	default:
+		return value
+	}
 }
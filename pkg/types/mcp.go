@@ -0,0 +1,73 @@
+package types
+
+// DefaultMCPProtocolVersion is the MCP revision julius speaks when a probe's
+// mcp section does not name one.
+const DefaultMCPProtocolVersion = "2025-06-18"
+
+// MCP transports a probe can detect.
+const (
+	MCPTransportStreamableHTTP = "streamable-http"
+	MCPTransportSSE            = "sse" // deprecated HTTP+SSE transport (2024-11-05)
+)
+
+// MCPConfig marks a probe as detecting a Model Context Protocol server. With
+// --mcp-enumerate, a match is followed by a full session (initialize,
+// notifications/initialized, then tools/list, resources/list and
+// prompts/list) whose inventory is recorded in Result.MCP. With
+// --mcp-discover, the probe is also tried at each of DiscoveryPaths below the
+// target.
+type MCPConfig struct {
+	ProtocolVersion string   `yaml:"protocol_version,omitempty"`
+	Transport       string   `yaml:"transport,omitempty"` // streamable-http (default) or sse
+	DiscoveryPaths  []string `yaml:"discovery_paths,omitempty"`
+}
+
+func (c *MCPConfig) GetTransport() string {
+	if c.Transport == "" {
+		return MCPTransportStreamableHTTP
+	}
+	return c.Transport
+}
+
+func (c *MCPConfig) GetProtocolVersion() string {
+	if c.ProtocolVersion == "" {
+		return DefaultMCPProtocolVersion
+	}
+	return c.ProtocolVersion
+}
+
+// MCPInventory is what an MCP server exposes to an unauthenticated client.
+type MCPInventory struct {
+	ProtocolVersion string        `json:"protocol_version,omitempty"`
+	ServerName      string        `json:"server_name,omitempty"`
+	ServerVersion   string        `json:"server_version,omitempty"`
+	Tools           []MCPTool     `json:"tools,omitempty"`
+	Resources       []MCPResource `json:"resources,omitempty"`
+	Prompts         []MCPPrompt   `json:"prompts,omitempty"`
+	Error           string        `json:"error,omitempty"`
+}
+
+type MCPTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"inputSchema,omitempty"`
+}
+
+type MCPResource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+type MCPPrompt struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Arguments   []MCPPromptArgument `json:"arguments,omitempty"`
+}
+
+type MCPPromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
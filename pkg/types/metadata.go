@@ -0,0 +1,45 @@
+package types
+
+import "time"
+
+// ScanReport is the enveloped form of json output: the results plus the
+// metadata needed to reproduce the scan and to show what was run, when.
+type ScanReport struct {
+	Metadata ScanMetadata `json:"metadata"`
+	Results  []Result     `json:"results"`
+}
+
+type ScanMetadata struct {
+	StartedAt    time.Time   `json:"started_at"`
+	FinishedAt   time.Time   `json:"finished_at"`
+	Version      string      `json:"julius_version"`
+	ProbeSetHash string      `json:"probe_set_hash"` // sha256 of the loaded probe definitions
+	Probes       []string    `json:"probes"`
+	TargetCount  int         `json:"target_count"`
+	Options      ScanOptions `json:"options"`
+}
+
+// ScanOptions records the CLI options that change what a scan sends or
+// reports. Header values that may carry credentials are redacted.
+type ScanOptions struct {
+	ProbesDir        string            `json:"probes_dir,omitempty"`
+	ExtraProbes      []string          `json:"extra_probes,omitempty"`
+	BasePaths        []string          `json:"base_paths,omitempty"`
+	Headers          map[string]string `json:"headers,omitempty"`
+	Timeout          int               `json:"timeout"`
+	Concurrency      int               `json:"concurrency"`
+	MaxResponseSize  int64             `json:"max_response_size"`
+	Insecure         bool              `json:"insecure,omitempty"`
+	CACert           string            `json:"ca_cert,omitempty"`
+	Augustus         bool              `json:"augustus,omitempty"`
+	Credentials      string            `json:"credentials,omitempty"` // path only, never the secrets
+	VerifyInference  bool              `json:"verify_inference,omitempty"`
+	MCPEnumerate     bool              `json:"mcp_enumerate,omitempty"`
+	MCPDiscover      bool              `json:"mcp_discover,omitempty"`
+	MCPOAuthExternal bool              `json:"mcp_oauth_external,omitempty"`
+	Probes           []string          `json:"probes,omitempty"`
+	ExcludeProbes    []string          `json:"exclude_probes,omitempty"`
+	Categories       []string          `json:"categories,omitempty"`
+	MinSpecificity   int               `json:"min_specificity,omitempty"`
+	Tags             []string          `json:"tags,omitempty"`
+}
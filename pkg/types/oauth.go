@@ -0,0 +1,33 @@
+package types
+
+// OAuthMetadata is the OAuth configuration an auth-gated server advertises
+// through RFC 9728 protected-resource metadata, with the RFC 8414 metadata of
+// each authorization server it names.
+type OAuthMetadata struct {
+	ResourceMetadataURL    string                     `json:"resource_metadata_url"`
+	Resource               string                     `json:"resource,omitempty"`
+	ResourceName           string                     `json:"resource_name,omitempty"`
+	ScopesSupported        []string                   `json:"scopes_supported,omitempty"`
+	BearerMethodsSupported []string                   `json:"bearer_methods_supported,omitempty"`
+	AuthorizationServers   []OAuthAuthorizationServer `json:"authorization_servers,omitempty"`
+	// Warnings flags misconfigurations worth a look: a resource identifier
+	// that is not the scanned URL, an issuer that does not match its metadata,
+	// plain-HTTP endpoints.
+	Warnings []string `json:"warnings,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// OAuthAuthorizationServer is one authorization server from the resource
+// metadata and the fields of its RFC 8414 metadata relevant to triage.
+type OAuthAuthorizationServer struct {
+	Issuer                        string   `json:"issuer"`
+	MetadataURL                   string   `json:"metadata_url,omitempty"`
+	ThirdParty                    bool     `json:"third_party"` // hosted on a different host than the resource
+	AuthorizationEndpoint         string   `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint                 string   `json:"token_endpoint,omitempty"`
+	RegistrationEndpoint          string   `json:"registration_endpoint,omitempty"`
+	ScopesSupported               []string `json:"scopes_supported,omitempty"`
+	GrantTypesSupported           []string `json:"grant_types_supported,omitempty"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported,omitempty"`
+	Error                         string   `json:"error,omitempty"`
+}
@@ -10,6 +10,22 @@ const (
 	SpecificityExact   = 100 // Definitive identification
 )
 
+// Probe categories.
+const (
+	CategorySelfHosted       = "self-hosted"
+	CategoryGateway          = "gateway"
+	CategoryMCP              = "mcp"
+	CategoryRAGOrchestration = "rag-orchestration"
+	CategoryCloudManaged     = "cloud-managed"
+	CategoryGeneric          = "generic"
+)
+
+// Categories lists the probe categories julius reports.
+var Categories = []string{
+	CategorySelfHosted, CategoryGateway, CategoryMCP,
+	CategoryRAGOrchestration, CategoryCloudManaged, CategoryGeneric,
+}
+
 const (
 	RequireAny = "any" // Default: match if ANY request succeeds
 	RequireAll = "all" // Match only if ALL requests succeed
@@ -17,8 +33,10 @@ const (
 
 type Probe struct {
 	Name        string          `yaml:"name"`
+	Extends     string          `yaml:"extends,omitempty"` // name of a probe to inherit from; see probe.Resolve
 	Description string          `yaml:"description"`
 	Category    string          `yaml:"category"`
+	Tags        []string        `yaml:"tags,omitempty"`
 	PortHint    int             `yaml:"port_hint"`
 	Specificity int             `yaml:"specificity"`       // 1-100, 0 treated as default (50)
 	Require     string          `yaml:"require,omitempty"` // "any" (default) or "all"
@@ -26,12 +44,38 @@ type Probe struct {
 	Requests    []Request       `yaml:"requests"`
 	Models      *ModelsConfig   `yaml:"models,omitempty"`
 	Augustus    *AugustusConfig `yaml:"augustus,omitempty"`
+	MCP         *MCPConfig      `yaml:"mcp,omitempty"`
+
+	// Source names where the probe was loaded from: its layer ("embedded"
+	// or a directory) when loaded with probe.LoadLayers, otherwise its file.
+	// It is not part of the definition and is excluded from the probe set hash.
+	Source string `yaml:"-" json:"-"`
 }
 
 func (p *Probe) RequiresAll() bool {
 	return strings.ToLower(p.Require) == RequireAll
 }
 
+// AuthPosture returns the authentication posture of a match. Under
+// require:any it is the matched request's posture. Under require:all every
+// request matched, so any auth-required request wins over open ones (a public
+// landing page does not make a gated API usable).
+func (p *Probe) AuthPosture(matched Request) string {
+	if !p.RequiresAll() {
+		return matched.AuthPosture()
+	}
+	posture := AuthUnknown
+	for _, req := range p.Requests {
+		switch req.AuthPosture() {
+		case AuthRequired:
+			return AuthRequired
+		case AuthOpen:
+			posture = AuthOpen
+		}
+	}
+	return posture
+}
+
 func (p *Probe) GetSpecificity() int {
 	if p.Specificity <= 0 {
 		return SpecificityMedium
@@ -40,56 +84,62 @@ func (p *Probe) GetSpecificity() int {
 }
 
 func (p *Probe) BuildGeneratorConfigs(target string, models []string) []GeneratorConfig {
+	return p.BuildGeneratorConfigsWithAPIKey(target, models, "")
+}
+
+// BuildGeneratorConfigsWithAPIKey is BuildGeneratorConfigs with $API_KEY
+// resolved to apiKey. An empty apiKey leaves the literal $API_KEY in place,
+// as BuildGeneratorConfigs does.
+func (p *Probe) BuildGeneratorConfigsWithAPIKey(target string, models []string, apiKey string) []GeneratorConfig {
 	if p.Augustus == nil {
 		return nil
 	}
 
 	if len(models) == 0 {
-		config := resolveGeneratorConfig(p.Augustus.ConfigTemplate, p.Augustus.Generator, target, "")
+		config := resolveGeneratorConfig(p.Augustus.ConfigTemplate, p.Augustus.Generator, target, "", apiKey)
 		return []GeneratorConfig{config}
 	}
 
 	configs := make([]GeneratorConfig, 0, len(models))
 	for _, model := range models {
-		config := resolveGeneratorConfig(p.Augustus.ConfigTemplate, p.Augustus.Generator, target, model)
+		config := resolveGeneratorConfig(p.Augustus.ConfigTemplate, p.Augustus.Generator, target, model, apiKey)
 		configs = append(configs, config)
 	}
 	return configs
 }
 
-func resolveGeneratorConfig(cfg GeneratorConfig, genType, target, model string) GeneratorConfig {
+func resolveGeneratorConfig(cfg GeneratorConfig, genType, target, model, apiKey string) GeneratorConfig {
 	cfg.Type = genType
-	cfg.Endpoint = resolveVars(cfg.Endpoint, target, model)
-	cfg.APIKey = resolveVars(cfg.APIKey, target, model)
-	cfg.Model = resolveVars(cfg.Model, target, model)
-	cfg.Body = resolveVars(cfg.Body, target, model)
-	cfg.ResponsePath = resolveVars(cfg.ResponsePath, target, model)
-	cfg.Proxy = resolveVars(cfg.Proxy, target, model)
+	cfg.Endpoint = resolveVars(cfg.Endpoint, target, model, apiKey)
+	cfg.APIKey = resolveVars(cfg.APIKey, target, model, apiKey)
+	cfg.Model = resolveVars(cfg.Model, target, model, apiKey)
+	cfg.Body = resolveVars(cfg.Body, target, model, apiKey)
+	cfg.ResponsePath = resolveVars(cfg.ResponsePath, target, model, apiKey)
+	cfg.Proxy = resolveVars(cfg.Proxy, target, model, apiKey)
 
 	if cfg.Headers != nil {
 		resolved := make(map[string]string, len(cfg.Headers))
 		for k, v := range cfg.Headers {
-			resolved[k] = resolveVars(v, target, model)
+			resolved[k] = resolveVars(v, target, model, apiKey)
 		}
 		cfg.Headers = resolved
 	}
 
-	if cfg.Extra != nil {
-		resolved := make(map[string]string, len(cfg.Extra))
-		for k, v := range cfg.Extra {
-			resolved[k] = resolveVars(v, target, model)
-		}
-		cfg.Extra = resolved
-	}
+	cfg.Extra = MapExtra(cfg.Extra, func(s string) string {
+		return resolveVars(s, target, model, apiKey)
+	})
 
 	return cfg
 }
 
-func resolveVars(s, target, model string) string {
+func resolveVars(s, target, model, apiKey string) string {
 	s = strings.ReplaceAll(s, "$TARGET", target)
 	if model != "" {
 		s = strings.ReplaceAll(s, "$MODEL", model)
 	}
+	if apiKey != "" {
+		s = strings.ReplaceAll(s, "$API_KEY", apiKey)
+	}
 	return s
 }
 
@@ -99,4 +149,46 @@ type ModelsConfig struct {
 	Headers map[string]string `yaml:"headers,omitempty"`
 	Body    string            `yaml:"body,omitempty"`
 	Extract string            `yaml:"extract"`
+	// Items and Fields describe structured per-model metadata. Items is a jq
+	// expression selecting one JSON value per model (e.g. ".models[]"); each
+	// Fields entry maps an output key to a jq expression evaluated against that
+	// value (e.g. family: ".details.family"). The "name" key populates
+	// ModelDetail.Name; every other key lands in ModelDetail.Fields.
+	Items      string            `yaml:"items,omitempty"`
+	Fields     map[string]string `yaml:"fields,omitempty"`
+	Pagination *PaginationConfig `yaml:"pagination,omitempty"`
+}
+
+const (
+	PaginationCursor = "cursor" // Next yields a cursor sent back in the Param query parameter
+	PaginationPage   = "page"   // Param carries an incrementing page number
+	PaginationLink   = "link"   // Follow the Link rel="next" header, or the URL yielded by Next
+
+	DefaultMaxPages = 10
+)
+
+// PaginationConfig describes how to walk a paged models listing. The first
+// request is always ModelsConfig.Path unchanged; subsequent pages are derived
+// from the previous response until a stop condition or MaxPages is reached.
+type PaginationConfig struct {
+	Type     string `yaml:"type"`
+	Param    string `yaml:"param,omitempty"`     // query parameter for cursor/page
+	Next     string `yaml:"next,omitempty"`      // jq: next cursor (cursor) or next URL (link)
+	HasMore  string `yaml:"has_more,omitempty"`  // jq: continue only while this yields true
+	Start    *int   `yaml:"start,omitempty"`     // page number of the first request (page), default 1
+	MaxPages int    `yaml:"max_pages,omitempty"` // safety cap on requests, default 10
+}
+
+func (p *PaginationConfig) GetMaxPages() int {
+	if p.MaxPages <= 0 {
+		return DefaultMaxPages
+	}
+	return p.MaxPages
+}
+
+func (p *PaginationConfig) GetStart() int {
+	if p.Start == nil {
+		return 1
+	}
+	return *p.Start
 }
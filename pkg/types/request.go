@@ -6,6 +6,13 @@ import (
 	"github.com/praetorian-inc/julius/pkg/rules"
 )
 
+// Request types. An sse request reads the response only up to its first
+// server-sent event, for endpoints that hold the stream open indefinitely.
+const (
+	RequestTypeHTTP = "http"
+	RequestTypeSSE  = "sse"
+)
+
 type Request struct {
 	Type     string            `yaml:"type"`
 	Path     string            `yaml:"path"`
@@ -13,11 +20,49 @@ type Request struct {
 	Body     string            `yaml:"body,omitempty"`
 	Headers  map[string]string `yaml:"headers,omitempty"`
 	RawMatch []rules.RawRule   `yaml:"match"`
+	// Auth annotates the authentication posture a match on this request
+	// implies. When empty it is derived from the request's status rule.
+	Auth string `yaml:"auth,omitempty"`
+}
+
+// Authentication postures reported in Result.Auth.
+const (
+	AuthOpen     = "open"          // the service answered without credentials
+	AuthRequired = "auth-required" // the service rejected the request as unauthenticated
+	AuthUnknown  = "unknown"       // the matched response says nothing either way
+)
+
+// AuthPostures lists the values accepted by a request's auth annotation.
+var AuthPostures = []string{AuthOpen, AuthRequired, AuthUnknown}
+
+// AuthPosture returns the posture a match on this request implies: the
+// explicit auth annotation if set, otherwise auth-required for a 401/403
+// status rule and unknown for anything else. A 2xx alone is not open: many
+// services serve health and metadata endpoints to anyone while gating their
+// API, so only an auth: open annotation reports open.
+func (r *Request) AuthPosture() string {
+	if r.Auth != "" {
+		return r.Auth
+	}
+	ruleList, err := r.GetRules()
+	if err != nil {
+		return AuthUnknown
+	}
+	for _, rule := range ruleList {
+		status, ok := rule.(*rules.StatusRule)
+		if !ok || status.IsNegated() {
+			continue
+		}
+		if status.Status == 401 || status.Status == 403 {
+			return AuthRequired
+		}
+	}
+	return AuthUnknown
 }
 
 func (r *Request) ApplyDefaults() {
 	if r.Type == "" {
-		r.Type = "http"
+		r.Type = RequestTypeHTTP
 	}
 	if r.Method == "" {
 		r.Method = "GET"
@@ -1,14 +1,52 @@
 package types
 
+import (
+	"strings"
+	"time"
+)
+
 type Result struct {
 	Target           string            `json:"target"`
 	Service          string            `json:"service"`
 	MatchedRequest   string            `json:"matched_request"`
 	Category         string            `json:"category"`
 	Specificity      int               `json:"specificity"`
+	Auth             string            `json:"auth,omitempty"` // open, auth-required or unknown
 	Models           []string          `json:"models,omitempty"`
+	ModelDetails     []ModelDetail     `json:"model_details,omitempty"`
 	GeneratorConfigs []GeneratorConfig `json:"generator_configs,omitempty"`
+	Inference        *InferenceCheck   `json:"inference,omitempty"`
+	MCP              *MCPInventory     `json:"mcp,omitempty"`
+	OAuth            *OAuthMetadata    `json:"oauth,omitempty"`
 	Error            string            `json:"error,omitempty"`
+	Timestamp        time.Time         `json:"timestamp,omitzero"` // when the probe matched
+}
+
+// BaseTarget recovers the scanned target from Target, which is the scanned
+// target with the matched request path appended.
+func (r Result) BaseTarget() string {
+	if r.MatchedRequest != "" {
+		return strings.TrimSuffix(r.Target, r.MatchedRequest)
+	}
+	return r.Target
+}
+
+// ModelDetail is the structured metadata extracted for one model via
+// models.items/models.fields. Field values keep their JSON types.
+type ModelDetail struct {
+	Name   string         `json:"name"`
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// InferenceCheck records the outcome of --verify-inference: one minimal,
+// unauthenticated completion request built from the probe's generator
+// template.
+type InferenceCheck struct {
+	Verified  bool   `json:"verified"`         // a completion was served without credentials
+	Model     string `json:"model,omitempty"`  // model that answered, as reported by the server
+	Status    int    `json:"status,omitempty"` // HTTP status of the completion request
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
 }
 
 type OutputWriter interface {
@@ -2,8 +2,10 @@ package types
 
 import (
 	"encoding/json"
+	"math"
 	"testing"
 
+	"github.com/praetorian-inc/julius/pkg/rules"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
@@ -246,7 +248,7 @@ func TestBuildGeneratorConfigs_MCPExtra(t *testing.T) {
 			Generator: "mcp",
 			ConfigTemplate: GeneratorConfig{
 				Endpoint: "$TARGET",
-				Extra: map[string]string{
+				Extra: map[string]any{
 					"transport": "auto",
 					"mode":      "list_tools",
 					"model_ref": "$MODEL", // proves extra values get $MODEL substitution
@@ -273,7 +275,7 @@ func TestGeneratorConfig_ExtraJSONRoundTrip(t *testing.T) {
 	original := GeneratorConfig{
 		Type:     "mcp",
 		Endpoint: "http://host/mcp",
-		Extra:    map[string]string{"transport": "auto", "mode": "list_tools"},
+		Extra:    map[string]any{"transport": "auto", "mode": "list_tools"},
 	}
 	b, err := json.Marshal(original)
 	require.NoError(t, err)
@@ -283,3 +285,121 @@ func TestGeneratorConfig_ExtraJSONRoundTrip(t *testing.T) {
 	require.NoError(t, json.Unmarshal(b, &got))
 	assert.Equal(t, original.Extra, got.Extra)
 }
+
+func TestBuildGeneratorConfigs_TypedExtra(t *testing.T) {
+	p := Probe{
+		Augustus: &AugustusConfig{
+			Generator: "rest",
+			ConfigTemplate: GeneratorConfig{
+				Endpoint: "$TARGET",
+				Extra: map[string]any{
+					"verify_tls":  false,
+					"max_retries": 3,
+					"stop":        []any{"$MODEL", "</s>"},
+					"options":     map[string]any{"base": "$TARGET/v1", "stream": true},
+				},
+			},
+		},
+	}
+
+	configs := p.BuildGeneratorConfigs("https://host", []string{"m1"})
+	require.Len(t, configs, 1)
+	b, err := json.Marshal(configs[0].Extra)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"verify_tls":false,"max_retries":3,"stop":["m1","</s>"],"options":{"base":"https://host/v1","stream":true}}`, string(b))
+
+	assert.Equal(t, []any{"$MODEL", "</s>"}, p.Augustus.ConfigTemplate.Extra["stop"], "resolution must not mutate the template")
+}
+
+func TestValidateExtra(t *testing.T) {
+	tests := []struct {
+		name      string
+		generator string
+		extra     map[string]any
+		wantErr   string
+	}{
+		{"typed values", "mcp", map[string]any{"transport": "auto", "verify_tls": false, "max_retries": uint64(2), "tags": []any{"a", 1}, "opts": map[string]any{"x": 1.5}}, ""},
+		{"nil extra", "mcp", nil, ""},
+		{"reserved key", "rest", map[string]any{"endpoint": "x"}, "extra.endpoint: use the endpoint field instead"},
+		{"null value", "rest", map[string]any{"x": nil}, "extra.x: null"},
+		{"nested null", "rest", map[string]any{"x": []any{map[string]any{"y": nil}}}, "extra.x[0].y: null"},
+		{"non-string map keys", "rest", map[string]any{"x": map[any]any{1: "a"}}, "extra.x: unsupported value"},
+		{"NaN", "rest", map[string]any{"x": math.NaN()}, "cannot be represented in JSON"},
+		{"wrong kind for generator key", "mcp", map[string]any{"transport": 1}, "extra.transport: mcp generator expects type string"},
+		{"string bool", "rest", map[string]any{"verify_tls": "false"}, "extra.verify_tls: rest generator expects type bool"},
+		{"fractional int", "rest", map[string]any{"max_retries": 1.5}, "expects type integer"},
+		{"generator key elsewhere is free-form", "rest", map[string]any{"transport": 1}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExtra(tt.generator, tt.extra)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestBuildGeneratorConfigsWithAPIKey(t *testing.T) {
+	p := Probe{
+		Augustus: &AugustusConfig{
+			Generator: "openai",
+			ConfigTemplate: GeneratorConfig{
+				Endpoint: "$TARGET",
+				Model:    "$MODEL",
+				APIKey:   "$API_KEY",
+				Headers:  map[string]string{"api-key": "$API_KEY"},
+			},
+		},
+	}
+
+	configs := p.BuildGeneratorConfigsWithAPIKey("https://host", []string{"gpt-4o"}, "sk-test")
+	require.Len(t, configs, 1)
+	assert.Equal(t, "sk-test", configs[0].APIKey)
+	assert.Equal(t, "sk-test", configs[0].Headers["api-key"])
+
+	configs = p.BuildGeneratorConfigs("https://host", []string{"gpt-4o"})
+	assert.Equal(t, "$API_KEY", configs[0].APIKey, "without a key the placeholder is left for the consumer")
+}
+
+func TestResult_BaseTarget(t *testing.T) {
+	assert.Equal(t, "https://host:8000", Result{Target: "https://host:8000/v1/models", MatchedRequest: "/v1/models"}.BaseTarget())
+	assert.Equal(t, "https://host/mcp", Result{Target: "https://host/mcp", MatchedRequest: ""}.BaseTarget())
+}
+
+func TestRequest_AuthPosture(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		want string
+	}{
+		{"200 without annotation", Request{RawMatch: []rules.RawRule{{Type: "status", Value: 200}}}, AuthUnknown},
+		{"200 annotated open", Request{Auth: AuthOpen, RawMatch: []rules.RawRule{{Type: "status", Value: 200}}}, AuthOpen},
+		{"401 requires auth", Request{RawMatch: []rules.RawRule{{Type: "status", Value: 401}}}, AuthRequired},
+		{"403 requires auth", Request{RawMatch: []rules.RawRule{{Type: "status", Value: 403}}}, AuthRequired},
+		{"negated status says nothing", Request{RawMatch: []rules.RawRule{{Type: "status", Value: 401, Not: true}}}, AuthUnknown},
+		{"no status rule", Request{RawMatch: []rules.RawRule{{Type: "body.contains", Value: "x"}}}, AuthUnknown},
+		{"422 without annotation", Request{RawMatch: []rules.RawRule{{Type: "status", Value: 422}}}, AuthUnknown},
+		{"annotation wins", Request{Auth: AuthOpen, RawMatch: []rules.RawRule{{Type: "status", Value: 422}}}, AuthOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.req.AuthPosture())
+		})
+	}
+}
+
+func TestProbe_AuthPosture_RequireAll(t *testing.T) {
+	open := Request{Path: "/", Auth: AuthOpen, RawMatch: []rules.RawRule{{Type: "status", Value: 200}}}
+	gated := Request{Path: "/api", RawMatch: []rules.RawRule{{Type: "status", Value: 401}}}
+
+	anyProbe := &Probe{Requests: []Request{open, gated}}
+	assert.Equal(t, AuthOpen, anyProbe.AuthPosture(open), "require:any reports the matched request")
+
+	allProbe := &Probe{Require: RequireAll, Requests: []Request{open, gated}}
+	assert.Equal(t, AuthRequired, allProbe.AuthPosture(open), "an auth-required request wins under require:all")
+}